@@ -0,0 +1,145 @@
+package ask
+
+import "sort"
+
+// Results holds every answer matched by a pattern, see ForAll.
+type Results []*Answer
+
+// ForAll returns the values matching pattern in source, which may use "*"
+// for any map key, "[*]" for any slice element and ".." for any number of
+// levels. Map keys are visited in sorted order. A path without wildcards
+// yields at most one result.
+func ForAll(source any, pattern string) Results {
+	var results Results
+	walkPattern(source, tokenizePattern(pattern), func(_ []string, value any) {
+		results = append(results, &Answer{value: value})
+	})
+	return results
+}
+
+// Values returns the raw values of the results.
+func (r Results) Values() []any {
+	values := make([]any, len(r))
+	for i, a := range r {
+		values[i] = a.value
+	}
+	return values
+}
+
+// Count returns the number of results.
+func (r Results) Count() int {
+	return len(r)
+}
+
+// Sum adds up the numeric results, widened to float64 like Answer.Float.
+// Other values are skipped.
+func (r Results) Sum() float64 {
+	sum := 0.0
+	for _, a := range r {
+		if f, ok := a.Float(0); ok {
+			sum += f
+		}
+	}
+	return sum
+}
+
+// Avg returns the mean of the numeric results. ok is false when there are
+// none.
+func (r Results) Avg() (avg float64, ok bool) {
+	n := 0
+	for _, a := range r {
+		if _, ok := a.Float(0); ok {
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return r.Sum() / float64(n), true
+}
+
+// Min returns the smallest numeric result. ok is false when there are none.
+func (r Results) Min() (min float64, ok bool) {
+	return r.extreme(func(a, b float64) bool { return a < b })
+}
+
+// Max returns the largest numeric result. ok is false when there are none.
+func (r Results) Max() (max float64, ok bool) {
+	return r.extreme(func(a, b float64) bool { return a > b })
+}
+
+func (r Results) extreme(better func(a, b float64) bool) (float64, bool) {
+	best, found := 0.0, false
+	for _, a := range r {
+		if f, ok := a.Float(0); ok && (!found || better(f, best)) {
+			best, found = f, true
+		}
+	}
+	return best, found
+}
+
+// Distinct returns the results without repeated values, keeping the first
+// of each. Values are compared with Equal, so 1 and 1.0 are the same.
+func (r Results) Distinct() Results {
+	var distinct Results
+	for _, a := range r {
+		seen := false
+		for _, d := range distinct {
+			if Equal(a.value, d.value) {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			distinct = append(distinct, a)
+		}
+	}
+	return distinct
+}
+
+// GroupBy groups the results by the text form of the value at keyPath
+// inside each of them, e.g. GroupBy("status") over "orders[*]". Results
+// without a value at keyPath are left out. Groups keep the result order.
+func (r Results) GroupBy(keyPath string) map[string]Results {
+	groups := make(map[string]Results)
+	for _, a := range r {
+		key := a.Path(keyPath)
+		if !key.Exists() {
+			continue
+		}
+		name := keyString(key.value)
+		groups[name] = append(groups[name], a)
+	}
+	return groups
+}
+
+// SortBy returns the results ordered by the value at keyPath inside each of
+// them, or by the results themselves when keyPath is empty. Numbers sort
+// before strings and compare by value; results without a sortable value
+// go last. The sort is stable and r is left unchanged.
+func (r Results) SortBy(keyPath string) Results {
+	sorted := append(Results(nil), r...)
+	rank := func(a *Answer) (int, float64, string) {
+		key := a.Path(keyPath)
+		if f, ok := key.Float(0); ok {
+			return 0, f, ""
+		}
+		if s, ok := key.String(""); ok {
+			return 1, 0, s
+		}
+		return 2, 0, ""
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, fi, si := rank(sorted[i])
+		rj, fj, sj := rank(sorted[j])
+		switch {
+		case ri != rj:
+			return ri < rj
+		case ri == 0:
+			return fi < fj
+		default:
+			return si < sj
+		}
+	})
+	return sorted
+}
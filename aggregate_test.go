@@ -0,0 +1,91 @@
+package ask
+
+import (
+	"reflect"
+	"testing"
+)
+
+const ordersJSON = `{
+	"orders": [
+		{"id": "a", "amount": 10, "status": "paid", "items": [{"sku": "X"}, {"sku": "Y"}]},
+		{"id": "b", "amount": 2.5, "status": "open", "items": [{"sku": "X"}]},
+		{"id": "c", "amount": 7, "status": "paid", "items": []},
+		{"id": "d", "status": "void", "items": [{"sku": "Z"}]}
+	],
+	"refunds": {"r1": {"amount": 3}, "r2": {"amount": 1}}
+}`
+
+func TestForAllAggregates(t *testing.T) {
+	doc := decodeJSON(t, ordersJSON)
+
+	amounts := ForAll(doc, "orders[*].amount")
+	if got := amounts.Count(); got != 3 {
+		t.Errorf("Count() = %d; want 3", got)
+	}
+	if got := amounts.Sum(); got != 19.5 {
+		t.Errorf("Sum() = %v; want 19.5", got)
+	}
+	if got, ok := amounts.Avg(); !ok || got != 6.5 {
+		t.Errorf("Avg() = (%v, %t); want (6.5, true)", got, ok)
+	}
+	if got, ok := amounts.Min(); !ok || got != 2.5 {
+		t.Errorf("Min() = (%v, %t); want (2.5, true)", got, ok)
+	}
+	if got, ok := amounts.Max(); !ok || got != 10 {
+		t.Errorf("Max() = (%v, %t); want (10, true)", got, ok)
+	}
+
+	if got := ForAll(doc, "refunds.*.amount").Sum(); got != 4 {
+		t.Errorf("Sum() over map wildcard = %v; want 4", got)
+	}
+	skus := ForAll(doc, "..sku").Distinct().Values()
+	if want := []interface{}{"X", "Y", "Z"}; !reflect.DeepEqual(skus, want) {
+		t.Errorf("Distinct() = %v; want %v", skus, want)
+	}
+
+	empty := ForAll(doc, "orders[*].missing")
+	if _, ok := empty.Avg(); ok || empty.Count() != 0 || empty.Sum() != 0 {
+		t.Errorf("aggregates over no results = count %d, sum %v, avg ok %t", empty.Count(), empty.Sum(), ok)
+	}
+	if _, ok := ForAll(doc, "orders[*].status").Max(); ok {
+		t.Errorf("Max() over strings ok = true; want false")
+	}
+}
+
+func TestResultsGroupAndSort(t *testing.T) {
+	doc := decodeJSON(t, ordersJSON)
+	orders := ForAll(doc, "orders[*]")
+
+	groups := orders.GroupBy("status")
+	if len(groups) != 3 || groups["paid"].Count() != 2 || groups["void"].Count() != 1 {
+		t.Errorf("GroupBy(\"status\") = %v", groups)
+	}
+	if got := groups["paid"].Sum(); got != 0 {
+		t.Errorf("Sum() of whole orders = %v; want 0", got)
+	}
+	ids := func(r Results) []interface{} {
+		var out []interface{}
+		for _, a := range r {
+			out = append(out, a.Path("id").Value())
+		}
+		return out
+	}
+	if got, want := ids(groups["paid"]), []interface{}{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("paid group = %v; want %v", got, want)
+	}
+
+	if got, want := ids(orders.SortBy("amount")), []interface{}{"b", "c", "a", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortBy(\"amount\") = %v; want %v", got, want)
+	}
+	if got, want := ids(orders.SortBy("status")), []interface{}{"b", "a", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortBy(\"status\") = %v; want %v", got, want)
+	}
+	if got, want := ids(orders), []interface{}{"a", "b", "c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortBy() reordered its receiver to %v", got)
+	}
+
+	mixed := ForAll([]interface{}{"b", 3, nil, "a", 1.5}, "[*]").SortBy("")
+	if got, want := mixed.Values(), []interface{}{1.5, 3, "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortBy(\"\") = %v; want %v", got, want)
+	}
+}
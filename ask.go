@@ -1,6 +1,7 @@
 package ask
 
 import (
+	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
@@ -16,30 +17,10 @@ type Answer struct {
 
 // For is used to select a path from source to return as answer.
 func For(source any, path string) *Answer {
-	partsInterface, ok := splitCache.Load(path)
-	var parts []string
-	if ok {
-		parts = partsInterface.([]string)
-	} else {
-		parts = tokenizePath(path)
-		splitCache.Store(path, parts)
-	}
-
 	current := source
 
-	for _, token := range parts {
-		if strings.HasPrefix(token, "[") && strings.HasSuffix(token, "]") {
-			// Handle array index
-			indexStr := strings.TrimSpace(token[1 : len(token)-1])
-			if index, err := strconv.Atoi(indexStr); err == nil {
-				current = accessSlice(current, index)
-			} else {
-				return &Answer{}
-			}
-		} else {
-			// Handle map key
-			current = accessMap(current, token)
-		}
+	for _, token := range splitPath(path) {
+		current = step(current, token)
 		if current == nil {
 			return &Answer{}
 		}
@@ -48,6 +29,50 @@ func For(source any, path string) *Answer {
 	return &Answer{value: current}
 }
 
+// Tokenize splits path into the tokens For follows: map keys and bracketed
+// slice indices such as "[0]". It lets packages building on ask walk their
+// own data structures with the same path grammar.
+func Tokenize(path string) []string {
+	return append([]string(nil), splitPath(path)...)
+}
+
+// JoinPath is the inverse of Tokenize: it writes tokens back as a path For
+// accepts, in the same form Flatten uses for its keys.
+func JoinPath(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		if b.Len() > 0 && !isIndexToken(token) {
+			b.WriteByte('.')
+		}
+		b.WriteString(token)
+	}
+	return b.String()
+}
+
+// splitPath returns the cached tokens of path, tokenizing it on first use.
+func splitPath(path string) []string {
+	if parts, ok := splitCache.Load(path); ok {
+		return parts.([]string)
+	}
+	parts := tokenizePath(path)
+	splitCache.Store(path, parts)
+	return parts
+}
+
+// step resolves a single path token against source, nil if it cannot be followed.
+func step(source any, token string) any {
+	if strings.HasPrefix(token, "[") && strings.HasSuffix(token, "]") {
+		// Handle array index
+		indexStr := strings.TrimSpace(token[1 : len(token)-1])
+		if index, err := strconv.Atoi(indexStr); err == nil {
+			return accessSlice(source, index)
+		}
+		return nil
+	}
+	// Handle map key
+	return accessMap(source, token)
+}
+
 func accessMap(source any, key string) any {
 	switch m := source.(type) {
 	case map[string]any:
@@ -56,19 +81,81 @@ func accessMap(source any, key string) any {
 		return m[key]
 	case map[string]int:
 		return m[key]
+	case map[any]any:
+		// Produced by YAML decoders; non-string keys match by their text form.
+		if v, ok := m[key]; ok {
+			return v
+		}
+		for k, v := range m {
+			if keyString(k) == key {
+				return v
+			}
+		}
+		return nil
 	}
 	// Use reflect as last resort
 	val := reflect.ValueOf(source)
 	if val.Kind() == reflect.Map {
-		keyVal := reflect.ValueOf(key)
-		valueVal := val.MapIndex(keyVal)
-		if valueVal.IsValid() {
-			return valueVal.Interface()
+		if keyVal, ok := mapKey(val.Type().Key(), key); ok {
+			valueVal := val.MapIndex(keyVal)
+			if valueVal.IsValid() {
+				return valueVal.Interface()
+			}
+		}
+		if val.Type().Key().Kind() == reflect.Interface {
+			// As for map[any]any, keys of other types match by their text form.
+			iter := val.MapRange()
+			for iter.Next() {
+				if keyString(iter.Key().Interface()) == key {
+					return iter.Value().Interface()
+				}
+			}
 		}
 	}
 	return nil
 }
 
+// mapKey converts a path key into a key of type t, so maps keyed by named
+// string types, numbers or booleans can be traversed too.
+func mapKey(t reflect.Type, key string) (reflect.Value, bool) {
+	keyVal := reflect.ValueOf(key)
+	switch t.Kind() {
+	case reflect.String:
+		return keyVal.Convert(t), true
+	case reflect.Interface:
+		return keyVal, keyVal.Type().Implements(t)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, 64)
+		v := reflect.New(t).Elem()
+		if err != nil || v.OverflowInt(i) {
+			return v, false
+		}
+		v.SetInt(i)
+		return v, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(key, 10, 64)
+		v := reflect.New(t).Elem()
+		if err != nil || v.OverflowUint(u) {
+			return v, false
+		}
+		v.SetUint(u)
+		return v, true
+	case reflect.Bool:
+		b, err := strconv.ParseBool(key)
+		return reflect.ValueOf(b).Convert(t), err == nil
+	}
+	return reflect.Value{}, false
+}
+
+// keyString returns the text form of a map key, used for maps whose keys
+// are not strings.
+func keyString(key any) string {
+	if s, ok := key.(string); ok {
+		return s
+	}
+	return fmt.Sprint(key)
+}
+
 func accessSlice(source any, index int) any {
 	val := reflect.ValueOf(source)
 	if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
@@ -195,6 +282,8 @@ func (a *Answer) Slice(def []any) ([]any, bool) {
 }
 
 // Map attempts to retrieve the answer as map[string]any.
+// Keys that are not strings, as produced by YAML decoders, are converted to
+// their text form.
 func (a *Answer) Map(def map[string]any) (map[string]any, bool) {
 	if a.value == nil {
 		return def, false
@@ -207,11 +296,7 @@ func (a *Answer) Map(def map[string]any) (map[string]any, bool) {
 		result := make(map[string]any)
 		iter := val.MapRange()
 		for iter.Next() {
-			k := iter.Key()
-			v := iter.Value()
-			if key, ok := k.Interface().(string); ok {
-				result[key] = v.Interface()
-			}
+			result[keyString(iter.Key().Interface())] = iter.Value().Interface()
 		}
 		return result, true
 	}
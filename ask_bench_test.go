@@ -270,3 +270,31 @@ func BenchmarkForInvalidPath(b *testing.B) {
 		_ = For(source, "invalid[")
 	}
 }
+
+func BenchmarkExtractSharedPrefix(b *testing.B) {
+	source := map[string]interface{}{
+		"payload": map[string]interface{}{
+			"user": map[string]interface{}{
+				"id":    1,
+				"name":  "value",
+				"email": "value",
+				"role":  "value",
+			},
+		},
+	}
+
+	paths := []string{"payload.user.id", "payload.user.name", "payload.user.email", "payload.user.role"}
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_ = Extract(source, paths...)
+	}
+}
+
+func BenchmarkForJSON(b *testing.B) {
+	data := []byte(`{"skip": {"a": [1, 2, 3], "b": "value"}, "a": [{"b": {"c": "value"}}]}`)
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		_ = ForJSON(data, "a[0].b.c")
+	}
+}
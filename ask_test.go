@@ -6,6 +6,8 @@ import (
 	"testing"
 )
 
+type namedKey string
+
 func TestFor(t *testing.T) {
 	source := map[string]interface{}{
 		"a": []interface{}{
@@ -81,6 +83,46 @@ func TestFor(t *testing.T) {
 			path:   "a[foo]",
 			want:   nil,
 		},
+		{
+			name: "YAML style map with non-string keys",
+			source: map[interface{}]interface{}{
+				"a": map[interface{}]interface{}{1: "one", true: "yes"},
+			},
+			path: "a.1",
+			want: "one",
+		},
+		{
+			name: "YAML style map with boolean key",
+			source: map[interface{}]interface{}{
+				"a": map[interface{}]interface{}{1: "one", true: "yes"},
+			},
+			path: "a.true",
+			want: "yes",
+		},
+		{
+			name:   "Interface keyed map with typed values",
+			source: map[interface{}]int{1: 5, "b": 6},
+			path:   "1",
+			want:   5,
+		},
+		{
+			name:   "Typed map with integer keys",
+			source: map[int]string{7: "seven"},
+			path:   "7",
+			want:   "seven",
+		},
+		{
+			name:   "Typed map with named string keys",
+			source: map[namedKey]int{"k": 5},
+			path:   "k",
+			want:   5,
+		},
+		{
+			name:   "Typed map with unconvertible key",
+			source: map[int]string{7: "seven"},
+			path:   "seven",
+			want:   nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -690,6 +732,7 @@ func TestMap(t *testing.T) {
 		"string":  "test",
 		"nil":     nil,
 		"invalid": 123,
+		"yaml":    map[interface{}]interface{}{1: "one", "b": true},
 	}
 
 	tests := []struct {
@@ -748,6 +791,14 @@ func TestMap(t *testing.T) {
 			wantLen: len(def),
 			wantOK:  false,
 		},
+		{
+			name:    "Non-string keys",
+			path:    "yaml",
+			def:     def,
+			want:    map[string]interface{}{"1": "one", "b": true},
+			wantLen: 2,
+			wantOK:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,205 @@
+// Package askhttp reads HTTP headers, query strings and form values with ask
+// paths.
+//
+// Header and parameter values are lists. A path ending at a list yields its
+// first value, and an index selects another one, so both "tag" and
+// "tag[0]" return the first tag while "tag[1]" returns the second.
+package askhttp
+
+import (
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lukaszraczylo/ask"
+)
+
+// maxIndex bounds bracketed indices such as "items[5]" so a crafted query
+// cannot allocate huge slices. Parameters above it are ignored.
+const maxIndex = 1000
+
+// Header selects path from h. The first key is matched case-insensitively
+// like http.Header.Get, so "content-type" finds "Content-Type".
+func Header(h http.Header, path string) *ask.Answer {
+	tokens := ask.Tokenize(path)
+	if len(tokens) == 0 {
+		return ask.For(h, "")
+	}
+	values, ok := h[textproto.CanonicalMIMEHeaderKey(tokens[0])]
+	if !ok {
+		// Keys set directly on the map may not be canonical.
+		values = h[tokens[0]]
+	}
+	return first(ask.For(values, strings.Join(tokens[1:], "")), len(tokens) == 1)
+}
+
+// Params is a tree of query or form parameters. Bracketed names are nested
+// the way common web frameworks do it: "filter[status]=x" is found at
+// "filter.status", "items[0][id]=1" at "items[0].id" and repeated
+// "tags[]=a&tags[]=b" at "tags" and "tags[1]".
+type Params struct {
+	tree map[string]any // leaves are []string
+}
+
+// ParseValues builds Params from url.Values. Names whose shape conflicts
+// with an earlier name, such as "a=1" followed by "a[b]=2", are ignored.
+func ParseValues(values url.Values) *Params {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tree := make(map[string]any)
+	for _, name := range names {
+		segments := splitName(name)
+		if len(segments) == 0 || segments[0] == "" {
+			continue
+		}
+		if next, ok := insert(tree, segments, values[name]); ok {
+			tree = next.(map[string]any)
+		}
+	}
+	return &Params{tree: tree}
+}
+
+// ParseQuery parses a raw query string into Params.
+func ParseQuery(query string) (*Params, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return ParseValues(values), nil
+}
+
+// For selects path from the parameters. A path ending at a parameter yields
+// its first value.
+func (p *Params) For(path string) *ask.Answer {
+	answer := ask.For(p.tree, path)
+	if _, ok := answer.Value().([]string); ok {
+		return first(answer, true)
+	}
+	return answer
+}
+
+// Values returns every value of the parameter at path.
+func (p *Params) Values(path string) []string {
+	values, _ := ask.For(p.tree, path).Value().([]string)
+	return values
+}
+
+// Map returns the parameters as nested maps and slices with []string leaves.
+func (p *Params) Map() map[string]any {
+	return p.tree
+}
+
+// Request gives handlers ask access to everything a request carries.
+type Request struct {
+	Query  *Params // URL query parameters
+	Form   *Params // body parameters, see http.Request.PostForm
+	header http.Header
+}
+
+// FromRequest parses the query and body of r. Multipart bodies are
+// included when r.ParseMultipartForm was called beforehand.
+func FromRequest(r *http.Request) (*Request, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	return &Request{
+		Query:  ParseValues(r.URL.Query()),
+		Form:   ParseValues(r.PostForm),
+		header: r.Header,
+	}, nil
+}
+
+// Header selects path from the request headers, see Header.
+func (r *Request) Header(path string) *ask.Answer {
+	return Header(r.header, path)
+}
+
+// first returns the first value of a list answer when whole is set,
+// otherwise the answer itself.
+func first(answer *ask.Answer, whole bool) *ask.Answer {
+	if !whole {
+		return answer
+	}
+	values, ok := answer.Value().([]string)
+	if !ok {
+		return answer
+	}
+	if len(values) == 0 {
+		return ask.For(nil, "")
+	}
+	return ask.For(values[0], "")
+}
+
+// splitName splits "items[0][id]" into "items", "0", "id". An empty
+// segment stands for "[]".
+func splitName(name string) []string {
+	open := strings.IndexByte(name, '[')
+	if open < 0 || !strings.HasSuffix(name, "]") {
+		return []string{name}
+	}
+	return append([]string{name[:open]}, strings.Split(name[open+1:len(name)-1], "][")...)
+}
+
+// insert stores values at segments inside node and returns the updated
+// node, or false when the shapes conflict.
+func insert(node any, segments []string, values []string) (any, bool) {
+	if len(segments) == 0 {
+		if node != nil {
+			return nil, false
+		}
+		return values, true
+	}
+	seg := segments[0]
+
+	if seg == "" {
+		// "[]" appends every value as its own element.
+		if len(segments) > 1 {
+			return nil, false
+		}
+		existing, ok := node.([]string)
+		if node != nil && !ok {
+			return nil, false
+		}
+		return append(existing, values...), true
+	}
+
+	if index, err := strconv.Atoi(seg); err == nil {
+		if index < 0 || index >= maxIndex {
+			return nil, false
+		}
+		list, ok := node.([]any)
+		if node != nil && !ok {
+			return nil, false
+		}
+		for len(list) <= index {
+			list = append(list, nil)
+		}
+		child, ok := insert(list[index], segments[1:], values)
+		if !ok {
+			return nil, false
+		}
+		list[index] = child
+		return list, true
+	}
+
+	m, ok := node.(map[string]any)
+	if node != nil && !ok {
+		return nil, false
+	}
+	if m == nil {
+		m = make(map[string]any)
+	}
+	child, ok := insert(m[seg], segments[1:], values)
+	if !ok {
+		return nil, false
+	}
+	m[seg] = child
+	return m, true
+}
@@ -0,0 +1,127 @@
+package askhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHeader(t *testing.T) {
+	h := http.Header{}
+	h.Add("Content-Type", "application/json")
+	h.Add("Accept", "text/html")
+	h.Add("Accept", "application/xml")
+	h["x-raw"] = []string{"raw"}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{path: "content-type", want: "application/json"},
+		{path: "CONTENT-TYPE", want: "application/json"},
+		{path: "accept", want: "text/html"},
+		{path: "accept[0]", want: "text/html"},
+		{path: "accept[1]", want: "application/xml"},
+		{path: "accept[2]", want: nil},
+		{path: "accept.x", want: nil},
+		{path: "x-raw", want: "raw"},
+		{path: "missing", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := Header(h, tt.path).Value(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Header(%q) = (%v); want (%v)", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	p, err := ParseQuery("q=go&tag=a&tag=b&filter[status]=open&filter[owner][id]=7" +
+		"&items[0][id]=1&items[1][id]=2&items[1][qty]=5&ids[]=3&ids[]=4&a=1&a[b]=2&big[5000]=x")
+	if err != nil {
+		t.Fatalf("ParseQuery() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{path: "q", want: "go"},
+		{path: "tag", want: "a"},
+		{path: "tag[1]", want: "b"},
+		{path: "filter.status", want: "open"},
+		{path: "filter.owner.id", want: "7"},
+		{path: "items[0].id", want: "1"},
+		{path: "items[1].qty", want: "5"},
+		{path: "ids", want: "3"},
+		{path: "ids[1]", want: "4"},
+		{path: "a", want: "1"},
+		{path: "a.b", want: nil},
+		{path: "big", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := p.For(tt.path).Value(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("For(%q) = (%v); want (%v)", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	if got, want := p.Values("tag"), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Values() = %v; want %v", got, want)
+	}
+	if n, ok := p.For("filter.owner.id").Int(0); ok {
+		t.Errorf("Int() = (%d, %t); parameters are strings", n, ok)
+	}
+	if items, ok := p.For("items").Slice(nil); !ok || len(items) != 2 {
+		t.Errorf("Slice() = (%v, %t); want 2 items", items, ok)
+	}
+
+	if _, err := ParseQuery("%zz"); err == nil {
+		t.Errorf("ParseQuery() on invalid query error = nil; want error")
+	}
+}
+
+func TestParseValuesEmpty(t *testing.T) {
+	p := ParseValues(url.Values{"[x]": {"1"}, "": {"2"}})
+	if len(p.Map()) != 0 {
+		t.Errorf("Map() = %v; want empty", p.Map())
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	body := strings.NewReader("user[name]=ann&user[roles][]=admin&user[roles][]=dev")
+	r := httptest.NewRequest(http.MethodPost, "/search?page=2&sort[by]=date", body)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Request-Id", "abc")
+
+	req, err := FromRequest(r)
+	if err != nil {
+		t.Fatalf("FromRequest() error = %v", err)
+	}
+
+	if got, _ := req.Query.For("page").String(""); got != "2" {
+		t.Errorf(`Query.For("page") = %q; want "2"`, got)
+	}
+	if got, _ := req.Query.For("sort.by").String(""); got != "date" {
+		t.Errorf(`Query.For("sort.by") = %q; want "date"`, got)
+	}
+	if got, _ := req.Form.For("user.name").String(""); got != "ann" {
+		t.Errorf(`Form.For("user.name") = %q; want "ann"`, got)
+	}
+	if got := req.Form.Values("user.roles"); !reflect.DeepEqual(got, []string{"admin", "dev"}) {
+		t.Errorf(`Form.Values("user.roles") = %v; want [admin dev]`, got)
+	}
+	if req.Form.For("page").Exists() {
+		t.Errorf(`Form.For("page") exists; query values must not leak into the form`)
+	}
+	if got, _ := req.Header("x-request-id").String(""); got != "abc" {
+		t.Errorf(`Header("x-request-id") = %q; want "abc"`, got)
+	}
+}
@@ -0,0 +1,246 @@
+package ask
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var bindCache sync.Map // reflect.Type -> []bindField
+
+// ErrMissing is reported through FieldError when a required path has no value.
+var ErrMissing = errors.New("required value is missing")
+
+// FieldError describes a struct field Bind could not fill.
+type FieldError struct {
+	Field string // Go field name, dotted for nested structs
+	Path  string // path from the ask tag
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("ask: field %s (%s): %v", e.Field, e.Path, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+type bindField struct {
+	index    []int
+	name     string
+	path     string
+	required bool
+	def      *string
+}
+
+// Bind fills the struct pointed to by target from source. Fields are selected
+// with `ask:"path"` tags; adding ",required" to the tag makes a missing value
+// an error, and a `default:"..."` tag supplies the value used when the path
+// does not exist. Defaults are taken verbatim for strings and parsed as JSON
+// for every other type, so `default:"[]"` yields an empty slice.
+//
+// Values are converted with the same rules as the Answer accessors, so a
+// float64 from encoding/json can fill an int field as long as it is whole
+// and fits.
+// All failing fields are reported together, each as a *FieldError.
+func Bind(source any, target any) error {
+	val := reflect.ValueOf(target)
+	if val.Kind() != reflect.Pointer || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ask: Bind target must be a non-nil pointer to struct, got %T", target)
+	}
+	return bindStruct(source, val.Elem(), "")
+}
+
+func bindStruct(source any, dst reflect.Value, prefix string) error {
+	var errs []error
+	for _, f := range bindFields(dst.Type()) {
+		name := prefix + f.name
+		answer := For(source, f.path)
+		if !answer.Exists() {
+			switch {
+			case f.def != nil:
+				if err := setDefault(fieldByIndex(dst, f.index), *f.def); err != nil {
+					errs = append(errs, &FieldError{Field: name, Path: f.path, Err: err})
+				}
+			case f.required:
+				errs = append(errs, &FieldError{Field: name, Path: f.path, Err: ErrMissing})
+			}
+			continue
+		}
+		if err := convertInto(answer.value, fieldByIndex(dst, f.index), name+"."); err != nil {
+			var fe *FieldError
+			if errors.As(err, &fe) {
+				// Nested struct errors already carry their own field and path.
+				errs = append(errs, err)
+				continue
+			}
+			errs = append(errs, &FieldError{Field: name, Path: f.path, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bindFields returns the cached binding plan for a struct type.
+func bindFields(t reflect.Type) []bindField {
+	if fields, ok := bindCache.Load(t); ok {
+		return fields.([]bindField)
+	}
+	var fields []bindField
+	collectBindFields(t, nil, "", &fields)
+	bindCache.Store(t, fields)
+	return fields
+}
+
+func collectBindFields(t reflect.Type, index []int, prefix string, fields *[]bindField) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		idx := append(append([]int(nil), index...), i)
+		tag, tagged := sf.Tag.Lookup("ask")
+		if !tagged {
+			// Untagged embedded structs are bound as if their fields were ours.
+			// Embedded pointers are allocated when one of their fields is set,
+			// which needs the embedded type to be exported.
+			switch {
+			case sf.Anonymous && sf.Type.Kind() == reflect.Struct:
+				collectBindFields(sf.Type, idx, prefix+sf.Name+".", fields)
+			case sf.Anonymous && sf.IsExported() && sf.Type.Kind() == reflect.Pointer && sf.Type.Elem().Kind() == reflect.Struct:
+				collectBindFields(sf.Type.Elem(), idx, prefix+sf.Name+".", fields)
+			}
+			continue
+		}
+		if tag == "-" || !sf.IsExported() {
+			continue
+		}
+		path, opts, _ := strings.Cut(tag, ",")
+		f := bindField{
+			index:    idx,
+			name:     prefix + sf.Name,
+			path:     path,
+			required: opts == "required",
+		}
+		if def, ok := sf.Tag.Lookup("default"); ok {
+			f.def = &def
+		}
+		*fields = append(*fields, f)
+	}
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex, allocating nil embedded
+// struct pointers on the way instead of panicking.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// truncates reports whether a holds a number the Int and Uint accessors
+// would have to cut short: a float with a fractional part or an infinity.
+func truncates(a *Answer) bool {
+	f, ok := a.Float(0)
+	return ok && (f != math.Trunc(f) || math.IsInf(f, 0))
+}
+
+func setDefault(dst reflect.Value, def string) error {
+	if dst.Kind() == reflect.String {
+		dst.SetString(def)
+		return nil
+	}
+	if err := json.Unmarshal([]byte(def), dst.Addr().Interface()); err != nil {
+		return fmt.Errorf("invalid default %q: %w", def, err)
+	}
+	return nil
+}
+
+// convertInto stores value in dst using the Answer accessor rules.
+func convertInto(value any, dst reflect.Value, prefix string) error {
+	if value == nil {
+		return nil
+	}
+	src := reflect.ValueOf(value)
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+
+	a := &Answer{value: value}
+	switch dst.Kind() {
+	case reflect.String:
+		if s, ok := a.String(""); ok {
+			dst.SetString(s)
+			return nil
+		}
+	case reflect.Bool:
+		if b, ok := a.Bool(false); ok {
+			dst.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := a.Int(0); ok && !truncates(a) && !dst.OverflowInt(i) {
+			dst.SetInt(i)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u, ok := a.Uint(0); ok && !truncates(a) && !dst.OverflowUint(u) {
+			dst.SetUint(u)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, ok := a.Float(0); ok && !dst.OverflowFloat(f) {
+			dst.SetFloat(f)
+			return nil
+		}
+	case reflect.Slice:
+		if items, ok := a.Slice(nil); ok {
+			s := reflect.MakeSlice(dst.Type(), len(items), len(items))
+			for i, item := range items {
+				if err := convertInto(item, s.Index(i), prefix); err != nil {
+					return fmt.Errorf("index %d: %w", i, err)
+				}
+			}
+			dst.Set(s)
+			return nil
+		}
+	case reflect.Map:
+		if dst.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if entries, ok := a.Map(nil); ok {
+			m := reflect.MakeMapWithSize(dst.Type(), len(entries))
+			for k, v := range entries {
+				elem := reflect.New(dst.Type().Elem()).Elem()
+				if err := convertInto(v, elem, prefix); err != nil {
+					return fmt.Errorf("key %q: %w", k, err)
+				}
+				m.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), elem)
+			}
+			dst.Set(m)
+			return nil
+		}
+	case reflect.Struct:
+		// Only maps can fill a struct, and only through its ask tags; a
+		// struct without any, such as time.Time, needs an assignable value.
+		if _, ok := asMap(value); ok && len(bindFields(dst.Type())) > 0 {
+			return bindStruct(value, dst, prefix)
+		}
+	case reflect.Pointer:
+		elem := reflect.New(dst.Type().Elem())
+		if err := convertInto(value, elem.Elem(), prefix); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+	return fmt.Errorf("cannot convert %T to %s", value, dst.Type())
+}
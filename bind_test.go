@@ -0,0 +1,181 @@
+package ask
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindUser struct {
+	Name string `ask:"name"`
+}
+
+type bindMeta struct {
+	Source string `ask:"meta.source" default:"unknown"`
+}
+
+type bindEvent struct {
+	bindMeta
+	UserID  int64             `ask:"payload.user.id,required"`
+	Score   float32           `ask:"payload.score"`
+	Active  bool              `ask:"payload.active"`
+	Tags    []string          `ask:"meta.tags" default:"[]"`
+	Counts  map[string]uint8  `ask:"meta.counts"`
+	User    bindUser          `ask:"payload.user"`
+	Owner   *bindUser         `ask:"payload.owner"`
+	Raw     interface{}       `ask:"payload"`
+	Labels  map[string]string `ask:"meta.labels"`
+	Skipped string            `ask:"-"`
+	ignored string            `ask:"payload.user.name"`
+}
+
+func TestBind(t *testing.T) {
+	var source map[string]interface{}
+	json.Unmarshal([]byte(`{
+		"payload": {
+			"user": {"id": 42, "name": "ann"},
+			"owner": {"name": "bob"},
+			"score": 0.5,
+			"active": true
+		},
+		"meta": {"counts": {"a": 1, "b": 2}, "labels": {"env": "prod"}}
+	}`), &source)
+
+	var ev bindEvent
+	ev.Skipped = "keep"
+	if err := Bind(source, &ev); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+
+	want := bindEvent{
+		bindMeta: bindMeta{Source: "unknown"},
+		UserID:   42,
+		Score:    0.5,
+		Active:   true,
+		Tags:     []string{},
+		Counts:   map[string]uint8{"a": 1, "b": 2},
+		User:     bindUser{Name: "ann"},
+		Owner:    &bindUser{Name: "bob"},
+		Raw:      source["payload"],
+		Labels:   map[string]string{"env": "prod"},
+		Skipped:  "keep",
+	}
+	if !reflect.DeepEqual(ev, want) {
+		t.Errorf("Bind() = (%+v); want (%+v)", ev, want)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	source := map[string]interface{}{
+		"payload": map[string]interface{}{
+			"score": "high",
+		},
+		"meta": map[string]interface{}{
+			"counts": map[string]interface{}{"a": 300},
+		},
+	}
+
+	var ev bindEvent
+	err := Bind(source, &ev)
+	if err == nil {
+		t.Fatal("Bind() error = nil; want error")
+	}
+	if !errors.Is(err, ErrMissing) {
+		t.Errorf("Bind() error = %v; want ErrMissing", err)
+	}
+	for _, part := range []string{"UserID", "Score", "Counts"} {
+		if !strings.Contains(err.Error(), part) {
+			t.Errorf("Bind() error = %v; want mention of %s", err, part)
+		}
+	}
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Path == "" {
+		t.Errorf("Bind() error = %v; want *FieldError with path", err)
+	}
+}
+
+type BindInner struct {
+	X int `ask:"x"`
+}
+
+func TestBindEmbeddedPointer(t *testing.T) {
+	type target struct {
+		*BindInner
+		Y int `ask:"y"`
+	}
+	var got target
+	if err := Bind(map[string]interface{}{"x": 1, "y": 2}, &got); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+	if got.BindInner == nil || got.X != 1 || got.Y != 2 {
+		t.Errorf("Bind() = %+v; want X 1 and Y 2", got)
+	}
+
+	var empty target
+	if err := Bind(map[string]interface{}{"y": 2}, &empty); err != nil || empty.BindInner != nil {
+		t.Errorf("Bind() = (%+v, %v); want embedded pointer left nil", empty, err)
+	}
+}
+
+func TestBindFractionalInt(t *testing.T) {
+	var target struct {
+		I int  `ask:"i"`
+		U uint `ask:"u"`
+		W int  `ask:"w"`
+	}
+	err := Bind(map[string]interface{}{"i": 1.7, "u": 0.5, "w": 3.0}, &target)
+	for _, part := range []string{"field I (i): cannot convert float64", "field U (u): cannot convert float64"} {
+		if err == nil || !strings.Contains(err.Error(), part) {
+			t.Errorf("Bind() error = %v; want %q", err, part)
+		}
+	}
+	if target.W != 3 {
+		t.Errorf("Bind() W = %d; want 3", target.W)
+	}
+}
+
+func TestBindStructMismatch(t *testing.T) {
+	var target struct {
+		P struct {
+			N int `ask:"n"`
+		} `ask:"p"`
+		When time.Time `ask:"when"`
+	}
+	err := Bind(map[string]interface{}{"p": "str", "when": map[string]interface{}{"wall": 1}}, &target)
+	if err == nil {
+		t.Fatal("Bind() error = nil; want error")
+	}
+	for _, part := range []string{"field P (p): cannot convert string", "field When (when): cannot convert map"} {
+		if !strings.Contains(err.Error(), part) {
+			t.Errorf("Bind() error = %v; want %q", err, part)
+		}
+	}
+
+	now := time.Now()
+	if err := Bind(map[string]interface{}{"when": now}, &target); err != nil || !target.When.Equal(now) {
+		t.Errorf("Bind() = (%v, %v); want time value assigned", target.When, err)
+	}
+}
+
+func TestBindInvalidTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		target interface{}
+	}{
+		{name: "Nil", target: nil},
+		{name: "Struct value", target: bindUser{}},
+		{name: "Pointer to non-struct", target: new(int)},
+		{name: "Nil pointer", target: (*bindUser)(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Bind(map[string]interface{}{}, tt.target); err == nil {
+				t.Errorf("Bind() error = nil; want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+// Command ask queries JSON, YAML, TOML, INI, .env and XML documents with the
+// same path grammar as the ask package.
+//
+//	ask 'a[0].b.c' < file.json
+//	ask -f file.json -o pretty a b.c
+//	ask -f file.json -int -default 0 items[3].count
+//	ask -i yaml spec.replicas < deployment.yaml
+//
+// Every path prints one line. The exit status is 1 when a path does not
+// exist (and no -default is given) and 2 on usage or input errors.
+//
+// "ask repl file.json" opens an interactive shell with cd and ls commands
+// and tab completion of keys and indices.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lukaszraczylo/ask"
+	"github.com/lukaszraczylo/ask/dotenv"
+	"github.com/lukaszraczylo/ask/ini"
+	"github.com/lukaszraczylo/ask/toml"
+	"github.com/lukaszraczylo/ask/xml"
+	"github.com/lukaszraczylo/ask/yaml"
+)
+
+const (
+	exitOK      = 0
+	exitMissing = 1
+	exitUsage   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+type options struct {
+	file     string
+	input    string
+	format   string
+	def      any
+	defText  string
+	hasDef   bool
+	paths    []string
+	isString bool
+	isInt    bool
+	isUint   bool
+	isFloat  bool
+	isBool   bool
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "repl" {
+		return runREPL(args[1:], stdin, stdout, stderr)
+	}
+
+	opts, err := parseArgs(args, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		fmt.Fprintln(stderr, "ask:", err)
+		return exitUsage
+	}
+
+	doc, err := readDocument(opts.file, opts.input, stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, "ask:", err)
+		return exitUsage
+	}
+
+	status := exitOK
+	for _, path := range opts.paths {
+		answer := ask.For(doc, path)
+		value, ok := opts.extract(answer)
+		if !ok {
+			if !opts.hasDef {
+				if answer.Exists() {
+					fmt.Fprintf(stderr, "ask: %s: not %s\n", path, opts.kind())
+				}
+				status = exitMissing
+				continue
+			}
+			value = opts.def
+		}
+		if err := write(stdout, value, opts.format); err != nil {
+			fmt.Fprintln(stderr, "ask:", err)
+			return exitUsage
+		}
+	}
+	return status
+}
+
+func parseArgs(args []string, stderr io.Writer) (*options, error) {
+	opts := &options{}
+	fs := flag.NewFlagSet("ask", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.file, "f", "-", "read the document from `file`, - for stdin")
+	fs.StringVar(&opts.input, "i", "auto", "input `format`: auto, json, yaml, toml, ini, env or xml; auto picks by file extension")
+	fs.StringVar(&opts.format, "o", "raw", "output `format`: raw, json or pretty")
+	fs.BoolVar(&opts.isString, "string", false, "require a string value")
+	fs.BoolVar(&opts.isInt, "int", false, "extract the value as an integer")
+	fs.BoolVar(&opts.isUint, "uint", false, "extract the value as an unsigned integer")
+	fs.BoolVar(&opts.isFloat, "float", false, "extract the value as a float")
+	fs.BoolVar(&opts.isBool, "bool", false, "require a boolean value")
+	fs.Func("default", "print `value` instead of failing when a path is missing, typed like -int, -uint, -float or -bool when given", func(s string) error {
+		opts.defText, opts.hasDef = s, true
+		return nil
+	})
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: ask [flags] path [path...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	switch opts.input {
+	case "auto", "json", "yaml", "toml", "ini", "env", "xml":
+	default:
+		return nil, fmt.Errorf("unknown input format %q", opts.input)
+	}
+	switch opts.format {
+	case "raw", "json", "pretty":
+	default:
+		return nil, fmt.Errorf("unknown output format %q", opts.format)
+	}
+	kinds := 0
+	for _, set := range []bool{opts.isString, opts.isInt, opts.isUint, opts.isFloat, opts.isBool} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds > 1 {
+		return nil, errors.New("only one of -string, -int, -uint, -float and -bool may be used")
+	}
+	if opts.hasDef {
+		def, err := opts.parseDefault(opts.defText)
+		if err != nil {
+			return nil, fmt.Errorf("-default %q is not %s", opts.defText, opts.kind())
+		}
+		opts.def = def
+	}
+	opts.paths = fs.Args()
+	if len(opts.paths) == 0 {
+		fs.Usage()
+		return nil, errors.New("no path given")
+	}
+	return opts, nil
+}
+
+// parseDefault converts the -default value to the type selected by the
+// typed extraction flags, so it prints like an extracted value would.
+func (o *options) parseDefault(s string) (any, error) {
+	switch {
+	case o.isInt:
+		return strconv.ParseInt(s, 10, 64)
+	case o.isUint:
+		return strconv.ParseUint(s, 10, 64)
+	case o.isFloat:
+		return strconv.ParseFloat(s, 64)
+	case o.isBool:
+		return strconv.ParseBool(s)
+	}
+	return s, nil
+}
+
+// kind describes the value the typed extraction flags ask for.
+func (o *options) kind() string {
+	switch {
+	case o.isString:
+		return "a string"
+	case o.isInt:
+		return "an int"
+	case o.isUint:
+		return "a uint"
+	case o.isFloat:
+		return "a float"
+	case o.isBool:
+		return "a bool"
+	}
+	return "a value"
+}
+
+// extract converts an answer according to the typed extraction flags.
+func (o *options) extract(a *ask.Answer) (any, bool) {
+	switch {
+	case o.isString:
+		return a.String("")
+	case o.isInt:
+		return a.Int(0)
+	case o.isUint:
+		return a.Uint(0)
+	case o.isFloat:
+		return a.Float(0)
+	case o.isBool:
+		return a.Bool(false)
+	}
+	return a.Value(), a.Exists()
+}
+
+// readDocument decodes the document in file, or stdin for "-". Input
+// "auto" picks the format from the file extension, JSON when unknown.
+func readDocument(file, input string, stdin io.Reader) (any, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if input == "auto" {
+		input = formatOf(file)
+	}
+
+	var doc any
+	switch input {
+	case "yaml":
+		doc, err = yaml.Decode(data)
+	case "toml":
+		doc, err = toml.Decode(data)
+	case "ini":
+		doc, err = ini.Decode(data)
+	case "env":
+		doc, err = dotenv.Decode(data)
+	case "xml":
+		doc, err = xml.Decode(data)
+	default:
+		doc, err = decodeJSON(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", file, err)
+	}
+	return doc, nil
+}
+
+// decodeJSON decodes data keeping integers exact, so large IDs survive:
+// whole numbers become int64, or uint64 above its range, and only the
+// rest float64.
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the document")
+	}
+	return exactNumbers(doc), nil
+}
+
+func exactNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return u
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, child := range v {
+			v[k] = exactNumbers(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = exactNumbers(child)
+		}
+	}
+	return value
+}
+
+func formatOf(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".toml":
+		return "toml"
+	case ".ini", ".cfg", ".conf":
+		return "ini"
+	case ".env":
+		return "env"
+	case ".xml":
+		return "xml"
+	}
+	if filepath.Base(file) == ".env" {
+		return "env"
+	}
+	return "json"
+}
+
+// write prints value on its own line. Raw output prints strings without
+// quotes and everything else as compact JSON.
+func write(w io.Writer, value any, format string) error {
+	if s, ok := value.(string); ok && format == "raw" {
+		_, err := fmt.Fprintln(w, s)
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if format == "pretty" {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(value); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testDocument = `{
+	"a": [{"b": {"c": 3}}],
+	"name": "ask",
+	"ratio": 0.25,
+	"ok": true,
+	"obj": {"x": [1, "<y>"]}
+}`
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantOut    string
+		wantStatus int
+	}{
+		{
+			name:    "Raw number",
+			args:    []string{"a[0].b.c"},
+			wantOut: "3\n",
+		},
+		{
+			name:    "Raw string is unquoted",
+			args:    []string{"name"},
+			wantOut: "ask\n",
+		},
+		{
+			name:    "JSON string is quoted",
+			args:    []string{"-o", "json", "name"},
+			wantOut: "\"ask\"\n",
+		},
+		{
+			name:    "Raw object is compact JSON",
+			args:    []string{"obj"},
+			wantOut: "{\"x\":[1,\"<y>\"]}\n",
+		},
+		{
+			name:    "Pretty output",
+			args:    []string{"-o", "pretty", "obj.x"},
+			wantOut: "[\n  1,\n  \"<y>\"\n]\n",
+		},
+		{
+			name:    "Multiple paths",
+			args:    []string{"name", "ok", "ratio"},
+			wantOut: "ask\ntrue\n0.25\n",
+		},
+		{
+			name:    "Typed int extraction",
+			args:    []string{"-int", "ratio"},
+			wantOut: "0\n",
+		},
+		{
+			name:       "Typed extraction of wrong type",
+			args:       []string{"-bool", "name"},
+			wantStatus: exitMissing,
+		},
+		{
+			name:       "Missing path",
+			args:       []string{"name", "missing"},
+			wantOut:    "ask\n",
+			wantStatus: exitMissing,
+		},
+		{
+			name:    "Missing path with default",
+			args:    []string{"-default", "none", "missing"},
+			wantOut: "none\n",
+		},
+		{
+			name:    "Typed default",
+			args:    []string{"-o", "json", "-int", "-default", "0", "missing"},
+			wantOut: "0\n",
+		},
+		{
+			name:    "Typed bool default",
+			args:    []string{"-o", "json", "-bool", "-default", "true", "missing"},
+			wantOut: "true\n",
+		},
+		{
+			name:       "Default of the wrong type",
+			args:       []string{"-uint", "-default", "-1", "missing"},
+			wantStatus: exitUsage,
+		},
+		{
+			name:       "No path",
+			args:       []string{},
+			wantStatus: exitUsage,
+		},
+		{
+			name:       "Unknown input format",
+			args:       []string{"-i", "csv", "a"},
+			wantStatus: exitUsage,
+		},
+		{
+			name:       "Unknown format",
+			args:       []string{"-o", "xml", "a"},
+			wantStatus: exitUsage,
+		},
+		{
+			name:       "Conflicting types",
+			args:       []string{"-int", "-bool", "a"},
+			wantStatus: exitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, strings.NewReader(testDocument), &stdout, &stderr)
+			if status != tt.wantStatus {
+				t.Errorf("run() status = %d; want %d (stderr: %s)", status, tt.wantStatus, stderr.String())
+			}
+			if got := stdout.String(); got != tt.wantOut {
+				t.Errorf("run() output = %q; want %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestRunWrongTypeMessage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"-int", "name", "missing"}, strings.NewReader(testDocument), &stdout, &stderr)
+	if status != exitMissing {
+		t.Errorf("run() status = %d; want %d", status, exitMissing)
+	}
+	if got, want := stderr.String(), "ask: name: not an int\n"; got != want {
+		t.Errorf("run() stderr = %q; want %q", got, want)
+	}
+}
+
+func TestRunLargeIntegers(t *testing.T) {
+	const doc = `{"ids": [9007199254740993, 12345678901234567890, -9007199254740993, 1.5]}`
+	tests := []struct {
+		args    []string
+		wantOut string
+	}{
+		{args: []string{"ids[0]", "ids[1]", "ids[2]", "ids[3]"}, wantOut: "9007199254740993\n12345678901234567890\n-9007199254740993\n1.5\n"},
+		{args: []string{"ids"}, wantOut: "[9007199254740993,12345678901234567890,-9007199254740993,1.5]\n"},
+		{args: []string{"-int", "ids[0]"}, wantOut: "9007199254740993\n"},
+		{args: []string{"-uint", "ids[1]"}, wantOut: "12345678901234567890\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if status := run(tt.args, strings.NewReader(doc), &stdout, &stderr); status != 0 {
+				t.Fatalf("run() status = %d (stderr: %s)", status, stderr.String())
+			}
+			if got := stdout.String(); got != tt.wantOut {
+				t.Errorf("run() output = %q; want %q", got, tt.wantOut)
+			}
+		})
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"a"}, strings.NewReader(`{"a": 1} {}`), &stdout, &stderr); status != exitUsage {
+		t.Errorf("run() status = %d for trailing data; want %d", status, exitUsage)
+	}
+}
+
+func TestRunFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "doc.json")
+	if err := os.WriteFile(file, []byte(testDocument), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"-f", file, "a[0].b.c"}, strings.NewReader(""), &stdout, &stderr); status != exitOK {
+		t.Fatalf("run() status = %d; want %d (stderr: %s)", status, exitOK, stderr.String())
+	}
+	if got := stdout.String(); got != "3\n" {
+		t.Errorf("run() output = %q; want %q", got, "3\n")
+	}
+
+	status := run([]string{"-f", filepath.Join(t.TempDir(), "missing.json"), "a"}, strings.NewReader(""), &stdout, &stderr)
+	if status != exitUsage {
+		t.Errorf("run() on missing file status = %d; want %d", status, exitUsage)
+	}
+
+	status = run([]string{"a"}, strings.NewReader("{not json"), &stdout, &stderr)
+	if status != exitUsage {
+		t.Errorf("run() on invalid input status = %d; want %d", status, exitUsage)
+	}
+}
+
+func TestRunYAML(t *testing.T) {
+	const doc = "spec:\n  replicas: 3\n  ports: [80, 443]\n"
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"-i", "yaml", "spec.ports[1]"}, strings.NewReader(doc), &stdout, &stderr); status != exitOK {
+		t.Fatalf("run() status = %d; want %d (stderr: %s)", status, exitOK, stderr.String())
+	}
+	if got := stdout.String(); got != "443\n" {
+		t.Errorf("run() output = %q; want %q", got, "443\n")
+	}
+
+	file := filepath.Join(t.TempDir(), "doc.yml")
+	if err := os.WriteFile(file, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if status := run([]string{"-f", file, "-int", "spec.replicas"}, strings.NewReader(""), &stdout, &stderr); status != exitOK {
+		t.Fatalf("run() status = %d; want %d (stderr: %s)", status, exitOK, stderr.String())
+	}
+	if got := stdout.String(); got != "3\n" {
+		t.Errorf("run() output = %q; want %q", got, "3\n")
+	}
+}
+
+func TestRunConfigFormats(t *testing.T) {
+	tests := []struct {
+		file string
+		data string
+	}{
+		{file: "app.toml", data: "[database.pool]\nmax = 10\n"},
+		{file: "app.ini", data: "[database.pool]\nmax = 10\n"},
+		{file: ".env", data: "database.pool.max=10\n"},
+		{file: "app.xml", data: "<database><pool max=\"x\"><max>10</max></pool></database>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(file, []byte(tt.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			var stdout, stderr bytes.Buffer
+			if status := run([]string{"-f", file, "database.pool.max"}, strings.NewReader(""), &stdout, &stderr); status != exitOK {
+				t.Fatalf("run() status = %d; want %d (stderr: %s)", status, exitOK, stderr.String())
+			}
+			if got := stdout.String(); got != "10\n" {
+				t.Errorf("run() output = %q; want %q", got, "10\n")
+			}
+		})
+	}
+}
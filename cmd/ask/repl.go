@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/lukaszraczylo/ask"
+	"golang.org/x/term"
+)
+
+const replHelp = `commands:
+  <path>        print the value at path, relative to the current node
+  /<path>       print the value at path, relative to the document root
+  ls [path]     list the children of the current node or of path
+  cd [path]     move into path; "cd .." goes up, "cd" or "cd /" to the root
+  pwd           print the current path
+  help          show this help
+  exit, quit    leave
+`
+
+// runREPL implements "ask repl file": an interactive shell for exploring a
+// document. Keys and indices are tab-completed when stdin is a terminal.
+func runREPL(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, "usage: ask repl file")
+		return exitUsage
+	}
+	doc, err := readDocument(args[0], "auto", stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, "ask:", err)
+		return exitUsage
+	}
+	s := &session{root: doc}
+
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		if err := s.interactive(f, stdout); err != nil {
+			fmt.Fprintln(stderr, "ask:", err)
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		if s.exec(scanner.Text(), stdout) {
+			break
+		}
+	}
+	return exitOK
+}
+
+func (s *session) interactive(f *os.File, stdout io.Writer) error {
+	fd := int(f.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{f, stdout}, s.prompt())
+	t.AutoCompleteCallback = s.complete
+	for {
+		line, err := t.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if s.exec(line, t) {
+			return nil
+		}
+		t.SetPrompt(s.prompt())
+	}
+}
+
+// session holds the document and the node the user has moved into.
+type session struct {
+	root any
+	cwd  []string // path segments such as "a" or "[0]"
+}
+
+func (s *session) prompt() string {
+	return "ask:" + ask.JoinPath(s.cwd) + "> "
+}
+
+// exec runs one command line and reports whether the session should end.
+func (s *session) exec(line string, w io.Writer) bool {
+	cmd, arg, _ := strings.Cut(strings.TrimSpace(line), " ")
+	arg = strings.TrimSpace(arg)
+	switch cmd {
+	case "":
+	case "exit", "quit":
+		return true
+	case "help":
+		fmt.Fprint(w, replHelp)
+	case "pwd":
+		fmt.Fprintln(w, ask.JoinPath(s.cwd))
+	case "cd":
+		if arg == "" {
+			arg = "/"
+		}
+		target := s.resolve(arg)
+		if !ask.For(s.root, ask.JoinPath(target)).Exists() {
+			fmt.Fprintf(w, "no such path: %s\n", arg)
+			break
+		}
+		s.cwd = target
+	case "ls":
+		s.list(w, ask.For(s.root, ask.JoinPath(s.resolve(arg))))
+	default:
+		answer := ask.For(s.root, ask.JoinPath(s.resolve(line)))
+		if !answer.Exists() {
+			fmt.Fprintf(w, "no such path: %s\n", strings.TrimSpace(line))
+			break
+		}
+		write(w, answer.Value(), "pretty")
+		fmt.Fprintf(w, "# %s\n", describe(answer.Value()))
+	}
+	return false
+}
+
+// resolve returns the segments of path relative to the current node. A
+// leading "/" starts at the root and each leading ".." (as in "../..")
+// moves one segment up.
+func (s *session) resolve(path string) []string {
+	path = strings.TrimSpace(path)
+	segments := append([]string(nil), s.cwd...)
+	if strings.HasPrefix(path, "/") {
+		segments, path = nil, path[1:]
+	}
+	for strings.HasPrefix(path, "..") {
+		if len(segments) > 0 {
+			segments = segments[:len(segments)-1]
+		}
+		path = strings.TrimPrefix(path[2:], "/")
+	}
+	return append(segments, ask.Tokenize(path)...)
+}
+
+func (s *session) list(w io.Writer, answer *ask.Answer) {
+	if !answer.Exists() {
+		fmt.Fprintln(w, "no such path")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, c := range childrenOf(answer) {
+		fmt.Fprintf(tw, "%s\t%s\n", c.name, describe(c.value))
+	}
+	tw.Flush()
+}
+
+// complete is the tab completion callback for term.Terminal. It extends the
+// last word of the line with the longest prefix shared by the matching keys
+// or indices of the node it refers to.
+func (s *session) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' || pos != len(line) {
+		return "", 0, false
+	}
+	start := strings.LastIndexByte(line, ' ') + 1
+	word := line[start:]
+
+	// Split the word into the parent path and the partial last segment.
+	parent, partial := "", word
+	if cut := strings.LastIndexAny(word, ".[/"); cut >= 0 {
+		switch word[cut] {
+		case '[':
+			parent, partial = word[:cut], word[cut:]
+		case '.':
+			parent, partial = word[:cut], word[cut+1:]
+		case '/':
+			parent, partial = word[:cut+1], word[cut+1:]
+		}
+	}
+	if strings.HasSuffix(parent, ".") {
+		// Still typing "..", nothing to complete yet.
+		return "", 0, false
+	}
+
+	var matches []string
+	for _, c := range childrenOf(ask.For(s.root, ask.JoinPath(s.resolve(parent)))) {
+		if strings.HasPrefix(c.name, partial) {
+			matches = append(matches, c.name)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+	completion := commonPrefix(matches)
+	if len(completion) <= len(partial) {
+		return "", 0, false
+	}
+	newLine := line + completion[len(partial):]
+	return newLine, len(newLine), true
+}
+
+type child struct {
+	name  string
+	value any
+}
+
+// childrenOf returns map entries sorted by key or slice elements as "[i]".
+func childrenOf(answer *ask.Answer) []child {
+	var children []child
+	if items, ok := answer.Slice(nil); ok {
+		for i, v := range items {
+			children = append(children, child{name: "[" + strconv.Itoa(i) + "]", value: v})
+		}
+		return children
+	}
+	if entries, ok := answer.Map(nil); ok {
+		for k, v := range entries {
+			children = append(children, child{name: k, value: v})
+		}
+		sort.Slice(children, func(i, j int) bool { return children[i].name < children[j].name })
+	}
+	return children
+}
+
+// describe summarises the type and size of a value.
+func describe(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("string, %d chars", utf8.RuneCountInString(v))
+	case bool:
+		return "bool"
+	}
+	a := ask.For(value, "")
+	if items, ok := a.Slice(nil); ok {
+		return fmt.Sprintf("array, %d items", len(items))
+	}
+	if entries, ok := a.Map(nil); ok {
+		return fmt.Sprintf("object, %d keys", len(entries))
+	}
+	if _, ok := a.Float(0); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lukaszraczylo/ask"
+)
+
+func newTestSession(t *testing.T) *session {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "doc.json")
+	if err := os.WriteFile(file, []byte(testDocument), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := readDocument(file, "auto", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &session{root: doc}
+}
+
+func TestSessionExec(t *testing.T) {
+	s := newTestSession(t)
+
+	steps := []struct {
+		line    string
+		wantOut string
+		wantCwd string
+	}{
+		{line: "name", wantOut: "\"ask\"\n# string, 3 chars\n"},
+		{line: "ls", wantOut: "a      array, 1 items\nname   string, 3 chars\nobj    object, 1 keys\nok     bool\nratio  number\n"},
+		{line: "cd a[0].b", wantCwd: "a[0].b"},
+		{line: "pwd", wantOut: "a[0].b\n", wantCwd: "a[0].b"},
+		{line: "c", wantOut: "3\n# number\n", wantCwd: "a[0].b"},
+		{line: "/ok", wantOut: "true\n# bool\n", wantCwd: "a[0].b"},
+		{line: "cd ..", wantCwd: "a[0]"},
+		{line: "cd missing", wantOut: "no such path: missing\n", wantCwd: "a[0]"},
+		{line: "cd ../../obj", wantCwd: "obj"},
+		{line: "ls x", wantOut: "[0]  number\n[1]  string, 3 chars\n", wantCwd: "obj"},
+		{line: "cd", wantCwd: ""},
+	}
+
+	for _, step := range steps {
+		var out bytes.Buffer
+		if s.exec(step.line, &out) {
+			t.Fatalf("exec(%q) ended the session", step.line)
+		}
+		if got := out.String(); got != step.wantOut {
+			t.Errorf("exec(%q) output = %q; want %q", step.line, got, step.wantOut)
+		}
+		if got := ask.JoinPath(s.cwd); got != step.wantCwd {
+			t.Errorf("exec(%q) cwd = %q; want %q", step.line, got, step.wantCwd)
+		}
+	}
+
+	if !s.exec("exit", &bytes.Buffer{}) {
+		t.Errorf("exec(exit) did not end the session")
+	}
+}
+
+func TestSessionComplete(t *testing.T) {
+	s := newTestSession(t)
+	s.root.(map[string]interface{})["objects"] = []interface{}{}
+
+	tests := []struct {
+		line   string
+		want   string
+		wantOK bool
+	}{
+		{line: "na", want: "name", wantOK: true},
+		{line: "cd o", want: "cd o", wantOK: false},
+		{line: "cd ob", want: "cd obj", wantOK: true},
+		{line: "obj.", want: "obj.x", wantOK: true},
+		{line: "a", want: "a", wantOK: false},
+		{line: "a[", want: "a[0]", wantOK: true},
+		{line: "ls a[0].", want: "ls a[0].b", wantOK: true},
+		{line: "zzz", wantOK: false},
+		{line: "cd ..", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, pos, ok := s.complete(tt.line, len(tt.line), '\t')
+			if ok != tt.wantOK {
+				t.Fatalf("complete(%q) ok = %t; want %t", tt.line, ok, tt.wantOK)
+			}
+			if ok && (got != tt.want || pos != len(got)) {
+				t.Errorf("complete(%q) = (%q, %d); want (%q, %d)", tt.line, got, pos, tt.want, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	s := &session{cwd: []string{"a", "[0]"}}
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: " b[1][2].c ", want: []string{"a", "[0]", "b", "[1]", "[2]", "c"}},
+		{path: "../x", want: []string{"a", "x"}},
+		{path: "/y[3]", want: []string{"y", "[3]"}},
+		{path: "", want: []string{"a", "[0]"}},
+	}
+	for _, tt := range tests {
+		if got := s.resolve(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("resolve(%q) = %v; want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRunREPL(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "doc.json")
+	if err := os.WriteFile(file, []byte(testDocument), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"repl", file}, strings.NewReader("cd obj\nx[1]\nquit\nname\n"), &stdout, &stderr)
+	if status != exitOK {
+		t.Fatalf("run(repl) status = %d; want %d (stderr: %s)", status, exitOK, stderr.String())
+	}
+	if want := "\"<y>\"\n# string, 3 chars\n"; stdout.String() != want {
+		t.Errorf("run(repl) output = %q; want %q", stdout.String(), want)
+	}
+
+	if status := run([]string{"repl"}, strings.NewReader(""), &stdout, &stderr); status != exitUsage {
+		t.Errorf("run(repl) without file status = %d; want %d", status, exitUsage)
+	}
+}
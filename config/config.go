@@ -0,0 +1,248 @@
+// Package config layers configuration sources into one document queried
+// with ask paths.
+//
+// Sources are merged in the order given to New, so later sources override
+// earlier ones:
+//
+//	cfg, err := config.New(
+//		config.Defaults(map[string]any{"db": map[string]any{"port": 5432}}),
+//		config.File("config.yaml"),
+//		config.Env("APP"),
+//		config.Flags(flag.CommandLine),
+//	)
+//	port, _ := cfg.For("db.port").Int(0)
+//	from, _ := cfg.Source("db.port") // "defaults", "config.yaml", "env" or "flags"
+//
+// Maps are merged key by key; any other value, including slices, replaces
+// what earlier sources held at its path.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lukaszraczylo/ask"
+	"github.com/lukaszraczylo/ask/dotenv"
+	"github.com/lukaszraczylo/ask/ini"
+	"github.com/lukaszraczylo/ask/internal/scalar"
+	"github.com/lukaszraczylo/ask/toml"
+	"github.com/lukaszraczylo/ask/xml"
+	"github.com/lukaszraczylo/ask/yaml"
+)
+
+// Source supplies one layer of configuration.
+type Source interface {
+	// Name identifies the source in Config.Source and in errors.
+	Name() string
+	// Load returns the values of the source as nested maps.
+	Load() (map[string]any, error)
+}
+
+type source struct {
+	name string
+	load func() (map[string]any, error)
+}
+
+func (s *source) Name() string                  { return s.name }
+func (s *source) Load() (map[string]any, error) { return s.load() }
+
+// Defaults is a source of fixed values, named "defaults".
+func Defaults(values map[string]any) Source {
+	return &source{name: "defaults", load: func() (map[string]any, error) {
+		return values, nil
+	}}
+}
+
+// File reads a configuration file named after its path. The format follows
+// the extension: .yaml and .yml, .toml, .ini, .cfg and .conf, .env (or a file
+// named .env), .xml, and JSON for anything else.
+func File(path string) Source {
+	return &source{name: path, load: func() (map[string]any, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return decodeFile(path, data)
+	}}
+}
+
+func decodeFile(path string, data []byte) (map[string]any, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch {
+	case ext == ".yaml" || ext == ".yml":
+		doc, err := yaml.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			return map[string]any{}, nil
+		}
+		m, ok := doc.(map[string]any)
+		if !ok {
+			return nil, errors.New("document is not a map")
+		}
+		return m, nil
+	case ext == ".toml":
+		return toml.Decode(data)
+	case ext == ".ini" || ext == ".cfg" || ext == ".conf":
+		return ini.Decode(data)
+	case ext == ".env" || filepath.Base(path) == ".env":
+		return dotenv.Decode(data)
+	case ext == ".xml":
+		return xml.Decode(data)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Env reads environment variables starting with prefix followed by an
+// underscore, named "env". The rest of the name is lowercased and each
+// underscore starts a nested key, so with prefix "APP" the variable
+// APP_DB_HOST is found at "db.host". Values are typed like dotenv values.
+//
+// A variable whose path is a prefix of another one, like APP_DB next to
+// APP_DB_HOST, cannot be stored and is ignored: the deeper one wins.
+func Env(prefix string) Source {
+	prefix = strings.TrimSuffix(prefix, "_") + "_"
+	return &source{name: "env", load: func() (map[string]any, error) {
+		flat := make(map[string]any)
+		for _, kv := range os.Environ() {
+			name, value, _ := strings.Cut(kv, "=")
+			if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+				continue
+			}
+			path := strings.ToLower(strings.ReplaceAll(name[len(prefix):], "_", "."))
+			flat[path] = scalar.Infer(value)
+		}
+		for path := range flat {
+			for i := range path {
+				if path[i] == '.' {
+					delete(flat, path[:i])
+				}
+			}
+		}
+		return ask.Unflatten(flat)
+	}}
+}
+
+// EnvVars reads the environment variables named in mapping and stores each
+// at the path it maps to, for names that do not follow a prefix scheme such
+// as {"DATABASE_URL": "db.url"}. The source is named "env".
+func EnvVars(mapping map[string]string) Source {
+	return &source{name: "env", load: func() (map[string]any, error) {
+		flat := make(map[string]any)
+		for name, path := range mapping {
+			if value, ok := os.LookupEnv(name); ok {
+				flat[path] = scalar.Infer(value)
+			}
+		}
+		return ask.Unflatten(flat)
+	}}
+}
+
+// Flags reads the flags of fs that were set on the command line, named
+// "flags". Flag names are used as paths, so -db.host sets "db.host".
+// Defaults of unset flags are left to the other sources.
+func Flags(fs *flag.FlagSet) Source {
+	return &source{name: "flags", load: func() (map[string]any, error) {
+		flat := make(map[string]any)
+		fs.Visit(func(f *flag.Flag) {
+			if getter, ok := f.Value.(flag.Getter); ok {
+				flat[f.Name] = getter.Get()
+				return
+			}
+			flat[f.Name] = scalar.Infer(f.Value.String())
+		})
+		return ask.Unflatten(flat)
+	}}
+}
+
+// Config is the merged result of several sources.
+type Config struct {
+	doc     map[string]any
+	origins map[string]string // flattened path to source name
+}
+
+// New loads every source and merges them, later sources taking precedence.
+func New(sources ...Source) (*Config, error) {
+	doc := make(map[string]any)
+	flats := make([]map[string]any, len(sources))
+	for i, src := range sources {
+		values, err := src.Load()
+		if err != nil {
+			return nil, fmt.Errorf("config: %s: %w", src.Name(), err)
+		}
+		merge(doc, values)
+		flats[i] = ask.Flatten(values)
+	}
+
+	// A value in the merged document came from the last source holding
+	// the same path, since any later write to it would have replaced it.
+	origins := make(map[string]string)
+	for path := range ask.Flatten(doc) {
+		for i := len(sources) - 1; i >= 0; i-- {
+			if _, ok := flats[i][path]; ok {
+				origins[path] = sources[i].Name()
+				break
+			}
+		}
+	}
+	return &Config{doc: doc, origins: origins}, nil
+}
+
+// For selects path from the merged configuration, see ask.For.
+func (c *Config) For(path string) *ask.Answer {
+	return ask.For(c.doc, path)
+}
+
+// Map returns the merged configuration, for example to pass to ask.Bind.
+func (c *Config) Map() map[string]any {
+	return c.doc
+}
+
+// Source reports the name of the source that supplied the value at path.
+// A map or slice is reported when every value in it came from the same
+// source.
+func (c *Config) Source(path string) (string, bool) {
+	key := ask.JoinPath(ask.Tokenize(path))
+	if name, ok := c.origins[key]; ok {
+		return name, true
+	}
+	found := ""
+	for p, name := range c.origins {
+		if key != "" && !strings.HasPrefix(p, key+".") && !strings.HasPrefix(p, key+"[") {
+			continue
+		}
+		if found != "" && found != name {
+			return "", false
+		}
+		found = name
+	}
+	return found, found != ""
+}
+
+// merge copies src into dst, merging nested maps. Maps in dst are always
+// fresh copies so sources are never modified.
+func merge(dst, src map[string]any) {
+	for k, v := range src {
+		child, ok := v.(map[string]any)
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		existing, ok := dst[k].(map[string]any)
+		if !ok {
+			existing = make(map[string]any, len(child))
+			dst[k] = existing
+		}
+		merge(existing, child)
+	}
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNew(t *testing.T) {
+	yamlFile := writeFile(t, "config.yaml", "db:\n  host: db.local\n  port: 5433\nfeatures: [a, b, c]\nname: file\n")
+	tomlFile := writeFile(t, "override.toml", "features = [\"x\"]\n[log]\nlevel = \"warn\"\n")
+	t.Setenv("APP_DB_HOST", "db.prod")
+	t.Setenv("APP_LOG_JSON", "true")
+	t.Setenv("OTHER_DB_HOST", "ignored")
+	t.Setenv("DATABASE_URL", "postgres://x")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("db.port", 1, "")
+	fs.String("name", "flag default", "")
+	if err := fs.Parse([]string{"-db.port=6000"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := New(
+		Defaults(map[string]any{
+			"db":   map[string]any{"host": "localhost", "port": 5432, "user": "admin"},
+			"name": "default",
+		}),
+		File(yamlFile),
+		File(tomlFile),
+		Env("APP"),
+		EnvVars(map[string]string{"DATABASE_URL": "db.url", "UNSET_VAR": "db.unset"}),
+		Flags(fs),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		want   interface{}
+		source string
+	}{
+		{path: "db.host", want: "db.prod", source: "env"},
+		{path: "db.port", want: 6000, source: "flags"},
+		{path: "db.user", want: "admin", source: "defaults"},
+		{path: "db.url", want: "postgres://x", source: "env"},
+		{path: "name", want: "file", source: yamlFile},
+		{path: "features", want: []interface{}{"x"}, source: tomlFile},
+		{path: "features[0]", want: "x", source: tomlFile},
+		{path: "log.level", want: "warn", source: tomlFile},
+		{path: "log.json", want: true, source: "env"},
+		{path: "db.unset", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := cfg.For(tt.path).Value(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("For(%q) = (%#v); want (%#v)", tt.path, got, tt.want)
+			}
+			source, ok := cfg.Source(tt.path)
+			if source != tt.source || ok != (tt.source != "") {
+				t.Errorf("Source(%q) = (%q, %t); want (%q, %t)", tt.path, source, ok, tt.source, tt.source != "")
+			}
+		})
+	}
+
+	if source, ok := cfg.Source("db"); ok {
+		t.Errorf("Source(\"db\") = (%q, true); want mixed sources to report false", source)
+	}
+	if source, ok := cfg.Source("log"); ok {
+		t.Errorf("Source(\"log\") = (%q, true); want mixed sources to report false", source)
+	}
+}
+
+func TestNewDoesNotModifySources(t *testing.T) {
+	defaults := map[string]any{"db": map[string]any{"host": "localhost"}}
+	cfg, err := New(Defaults(defaults), Defaults(map[string]any{"db": map[string]any{"port": 1}}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, ok := defaults["db"].(map[string]any)["port"]; ok {
+		t.Errorf("New() modified the defaults map")
+	}
+	if source, ok := cfg.Source("db.port"); !ok || source != "defaults" {
+		t.Errorf("Source(\"db.port\") = (%q, %t); want (\"defaults\", true)", source, ok)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source Source
+	}{
+		{name: "Missing file", source: File(filepath.Join(t.TempDir(), "missing.json"))},
+		{name: "Invalid JSON", source: File(writeFile(t, "bad.json", "{"))},
+		{name: "YAML scalar", source: File(writeFile(t, "scalar.yml", "just text"))},
+		{name: "Invalid TOML", source: File(writeFile(t, "bad.toml", "a = "))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.source); err == nil {
+				t.Errorf("New() error = nil; want error")
+			}
+		})
+	}
+}
+
+func TestEnvConflict(t *testing.T) {
+	t.Setenv("CONFLICT_A", "1")
+	t.Setenv("CONFLICT_A_B", "2")
+	t.Setenv("CONFLICT_A_B_C", "3")
+	t.Setenv("CONFLICT_D", "4")
+	cfg, err := New(Env("CONFLICT"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	want := map[string]any{"a": map[string]any{"b": map[string]any{"c": int64(3)}}, "d": int64(4)}
+	if !reflect.DeepEqual(cfg.Map(), want) {
+		t.Errorf("Map() = %v; want %v", cfg.Map(), want)
+	}
+}
+
+type failing struct{}
+
+func (failing) Name() string                  { return "remote" }
+func (failing) Load() (map[string]any, error) { return nil, errors.New("unavailable") }
+
+func TestCustomSource(t *testing.T) {
+	_, err := New(Defaults(nil), failing{})
+	if err == nil || err.Error() != "config: remote: unavailable" {
+		t.Errorf("New() error = %v; want \"config: remote: unavailable\"", err)
+	}
+}
@@ -0,0 +1,316 @@
+package ask
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ChangeKind tells how a value differs between two documents.
+type ChangeKind int
+
+const (
+	// Added values exist only in the new document.
+	Added ChangeKind = iota + 1
+	// Removed values exist only in the old document.
+	Removed
+	// Changed values exist in both documents with different values.
+	Changed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// Change is a single difference reported by Diff. Old is nil for Added
+// values and New is nil for Removed ones.
+type Change struct {
+	Path string
+	Kind ChangeKind
+	Old  any
+	New  any
+}
+
+type compareConfig struct {
+	ignore       [][]string
+	sets         bool
+	matchBy      []sliceKey
+	looseNumbers bool
+	firstOnly    bool // stop at the first change, for Equal
+}
+
+type sliceKey struct {
+	pattern []string
+	key     string
+}
+
+// CompareOption configures Diff and Equal.
+type CompareOption func(*compareConfig)
+
+// IgnorePaths skips the given paths and everything below them. Paths may use
+// "*" for any map key and "[*]" for any slice element.
+func IgnorePaths(paths ...string) CompareOption {
+	return func(c *compareConfig) {
+		for _, path := range paths {
+			c.ignore = append(c.ignore, splitPath(path))
+		}
+	}
+}
+
+// SlicesAsSets compares slices without regard to order. Elements are
+// reported as added or removed at their index in the new or old slice.
+func SlicesAsSets() CompareOption {
+	return func(c *compareConfig) {
+		c.sets = true
+	}
+}
+
+// MatchSliceBy pairs the elements of slices at pattern by the value at
+// keyPath inside each element, e.g. MatchSliceBy("users", "id"), so that
+// reordering a slice is not a change and edits are reported per element.
+// Changes inside a paired element use its index in the new slice.
+func MatchSliceBy(pattern, keyPath string) CompareOption {
+	return func(c *compareConfig) {
+		c.matchBy = append(c.matchBy, sliceKey{pattern: splitPath(pattern), key: keyPath})
+	}
+}
+
+// LooseNumbers treats numbers of different Go types as equal when they have
+// the same value, e.g. int 1 and float64 1.
+func LooseNumbers() CompareOption {
+	return func(c *compareConfig) {
+		c.looseNumbers = true
+	}
+}
+
+func newCompareConfig(opts []CompareOption) *compareConfig {
+	c := &compareConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Diff lists the differences between two documents with paths in For
+// syntax. Maps are compared key by key and slices element by element
+// unless SlicesAsSets or MatchSliceBy applies. A value replaced by one of a
+// different shape, such as a map by a string, is a single change.
+func Diff(old, new any, opts ...CompareOption) []Change {
+	c := newCompareConfig(opts)
+	var changes []Change
+	c.diff(nil, old, new, &changes)
+	return changes
+}
+
+func (c *compareConfig) diff(path []string, old, new any, changes *[]Change) {
+	if c.firstOnly && len(*changes) > 0 {
+		return
+	}
+	for _, pattern := range c.ignore {
+		if matchPath(pattern, path) {
+			return
+		}
+	}
+
+	if oldMap, ok := asMap(old); ok {
+		if newMap, ok := asMap(new); ok {
+			c.diffMaps(path, oldMap, newMap, changes)
+			return
+		}
+	}
+	if oldList, ok := asSlice(old); ok {
+		if newList, ok := asSlice(new); ok {
+			c.diffSlices(path, oldList, newList, changes)
+			return
+		}
+	}
+	if !c.equalValues(old, new) {
+		*changes = append(*changes, Change{Path: JoinPath(path), Kind: Changed, Old: old, New: new})
+	}
+}
+
+func (c *compareConfig) diffMaps(path []string, old, new map[string]any, changes *[]Change) {
+	keys := make([]string, 0, len(old)+len(new))
+	for k := range old {
+		keys = append(keys, k)
+	}
+	for k := range new {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if c.firstOnly && len(*changes) > 0 {
+			return
+		}
+		oldValue, inOld := old[k]
+		newValue, inNew := new[k]
+		child := append(path[:len(path):len(path)], k)
+		switch {
+		case !inNew:
+			c.report(child, Removed, oldValue, nil, changes)
+		case !inOld:
+			c.report(child, Added, nil, newValue, changes)
+		default:
+			c.diff(child, oldValue, newValue, changes)
+		}
+	}
+}
+
+func (c *compareConfig) diffSlices(path []string, old, new []any, changes *[]Change) {
+	var same func(path []string, a, b any) bool
+	for _, rule := range c.matchBy {
+		if matchPath(rule.pattern, path) {
+			key := rule.key
+			same = func(_ []string, a, b any) bool {
+				ka, kb := For(a, key), For(b, key)
+				return ka.Exists() && kb.Exists() && c.equalValues(ka.Value(), kb.Value())
+			}
+			break
+		}
+	}
+	if same == nil && c.sets {
+		same = func(path []string, a, b any) bool {
+			var found []Change
+			c.diff(path, a, b, &found)
+			return len(found) == 0
+		}
+	}
+
+	if same == nil {
+		for i := 0; i < len(old) || i < len(new); i++ {
+			child := append(path[:len(path):len(path)], indexToken(i))
+			switch {
+			case i >= len(new):
+				c.report(child, Removed, old[i], nil, changes)
+			case i >= len(old):
+				c.report(child, Added, nil, new[i], changes)
+			default:
+				c.diff(child, old[i], new[i], changes)
+			}
+		}
+		return
+	}
+
+	// Pair every new element with the first unused old element that is the
+	// same, then report what is left over.
+	used := make([]bool, len(old))
+	var added []int
+	for j, b := range new {
+		child := append(path[:len(path):len(path)], indexToken(j))
+		paired := false
+		for i, a := range old {
+			if !used[i] && same(child, a, b) {
+				used[i], paired = true, true
+				c.diff(child, a, b, changes)
+				break
+			}
+		}
+		if !paired {
+			added = append(added, j)
+		}
+	}
+	for i, a := range old {
+		if !used[i] {
+			c.report(append(path[:len(path):len(path)], indexToken(i)), Removed, a, nil, changes)
+		}
+	}
+	for _, j := range added {
+		c.report(append(path[:len(path):len(path)], indexToken(j)), Added, nil, new[j], changes)
+	}
+}
+
+// report records an added or removed value unless its path is ignored.
+func (c *compareConfig) report(path []string, kind ChangeKind, old, new any, changes *[]Change) {
+	for _, pattern := range c.ignore {
+		if matchPath(pattern, path) {
+			return
+		}
+	}
+	*changes = append(*changes, Change{Path: JoinPath(path), Kind: kind, Old: old, New: new})
+}
+
+// equalValues compares two values that are not both maps or both slices.
+func (c *compareConfig) equalValues(a, b any) bool {
+	if c.looseNumbers {
+		if equal, ok := numbersEqual(a, b); ok {
+			return equal
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// numbersEqual compares two numbers by value. ok is false unless both are
+// numbers.
+func numbersEqual(a, b any) (equal, ok bool) {
+	if !isNumber(a) || !isNumber(b) {
+		return false, false
+	}
+	ai, aInt := exactInt(a)
+	bi, bInt := exactInt(b)
+	if aInt && bInt {
+		return ai == bi, true
+	}
+	au, aUint := a.(uint64)
+	bu, bUint := b.(uint64)
+	if aUint && bUint {
+		return au == bu, true
+	}
+	af, _ := (&Answer{value: a}).Float(0)
+	bf, _ := (&Answer{value: b}).Float(0)
+	return af == bf, true
+}
+
+func isNumber(v any) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return true
+	}
+	return false
+}
+
+// exactInt returns v as int64 when it is an integer type that fits.
+func exactInt(v any) (int64, bool) {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return (&Answer{value: v}).Int(0)
+	}
+	return 0, false
+}
+
+// asMap returns map values as map[string]any, see Answer.Map.
+func asMap(v any) (map[string]any, bool) {
+	if v == nil {
+		return nil, false
+	}
+	if m, ok := v.(map[string]any); ok {
+		return m, true
+	}
+	if reflect.TypeOf(v).Kind() != reflect.Map {
+		return nil, false
+	}
+	return (&Answer{value: v}).Map(nil)
+}
+
+// asSlice returns slice and array values as []any, see Answer.Slice.
+func asSlice(v any) ([]any, bool) {
+	if v == nil {
+		return nil, false
+	}
+	if s, ok := v.([]any); ok {
+		return s, true
+	}
+	if k := reflect.TypeOf(v).Kind(); k != reflect.Slice && k != reflect.Array {
+		return nil, false
+	}
+	return (&Answer{value: v}).Slice(nil)
+}
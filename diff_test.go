@@ -0,0 +1,131 @@
+package ask
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := map[string]interface{}{
+		"name":    "svc",
+		"version": float64(1),
+		"owner":   map[string]interface{}{"team": "core", "email": "core@x"},
+		"tags":    []interface{}{"a", "b", "c"},
+		"updated": "monday",
+		"users": []interface{}{
+			map[string]interface{}{"id": float64(1), "role": "admin"},
+			map[string]interface{}{"id": float64(2), "role": "user"},
+			map[string]interface{}{"id": float64(3), "role": "user"},
+		},
+	}
+	new := map[string]interface{}{
+		"name":    "svc",
+		"version": 2,
+		"owner":   "core",
+		"tags":    []string{"c", "a", "d"},
+		"updated": "tuesday",
+		"users": []interface{}{
+			map[string]interface{}{"id": 3, "role": "user"},
+			map[string]interface{}{"id": 1, "role": "owner"},
+			map[string]interface{}{"id": 4, "role": "user"},
+		},
+		"region": "eu",
+	}
+
+	tests := []struct {
+		name string
+		old  interface{}
+		new  interface{}
+		opts []CompareOption
+		want []Change
+	}{
+		{
+			name: "Identical",
+			old:  old,
+			new:  old,
+		},
+		{
+			name: "Positional",
+			old:  map[string]interface{}{"a": []interface{}{1, 2, 3}, "b": map[string]interface{}{"c": 1}},
+			new:  map[string]interface{}{"a": []interface{}{1, 5}, "b": map[string]interface{}{"d": 1}},
+			want: []Change{
+				{Path: "a[1]", Kind: Changed, Old: 2, New: 5},
+				{Path: "a[2]", Kind: Removed, Old: 3},
+				{Path: "b.c", Kind: Removed, Old: 1},
+				{Path: "b.d", Kind: Added, New: 1},
+			},
+		},
+		{
+			name: "Strict numbers",
+			old:  map[string]interface{}{"n": float64(1)},
+			new:  map[string]interface{}{"n": 1},
+			want: []Change{{Path: "n", Kind: Changed, Old: float64(1), New: 1}},
+		},
+		{
+			name: "Loose numbers",
+			old:  map[string]interface{}{"n": float64(1), "m": int64(2), "u": uint8(3)},
+			new:  map[string]interface{}{"n": 1, "m": float32(2.5), "u": 3},
+			opts: []CompareOption{LooseNumbers()},
+			want: []Change{{Path: "m", Kind: Changed, Old: int64(2), New: float32(2.5)}},
+		},
+		{
+			name: "Options together",
+			old:  old,
+			new:  new,
+			opts: []CompareOption{
+				LooseNumbers(),
+				IgnorePaths("updated", "users[*].role"),
+				SlicesAsSets(),
+				MatchSliceBy("users", "id"),
+			},
+			want: []Change{
+				{Path: "owner", Kind: Changed, Old: old["owner"], New: "core"},
+				{Path: "region", Kind: Added, New: "eu"},
+				{Path: "tags[1]", Kind: Removed, Old: "b"},
+				{Path: "tags[2]", Kind: Added, New: "d"},
+				{Path: "users[1]", Kind: Removed, Old: old["users"].([]interface{})[1]},
+				{Path: "users[2]", Kind: Added, New: new["users"].([]interface{})[2]},
+				{Path: "version", Kind: Changed, Old: float64(1), New: 2},
+			},
+		},
+		{
+			name: "Matched elements report inner changes",
+			old:  old["users"],
+			new:  new["users"],
+			opts: []CompareOption{LooseNumbers(), MatchSliceBy("", "id")},
+			want: []Change{
+				{Path: "[1].role", Kind: Changed, Old: "admin", New: "owner"},
+				{Path: "[1]", Kind: Removed, Old: old["users"].([]interface{})[1]},
+				{Path: "[2]", Kind: Added, New: new["users"].([]interface{})[2]},
+			},
+		},
+		{
+			name: "Ignored subtree",
+			old:  map[string]interface{}{"meta": map[string]interface{}{"a": 1}, "x": 1},
+			new:  map[string]interface{}{"meta": map[string]interface{}{"b": 2}, "x": 1},
+			opts: []CompareOption{IgnorePaths("meta")},
+		},
+		{
+			name: "Root replaced",
+			old:  "a",
+			new:  []interface{}{"a"},
+			want: []Change{{Path: "", Kind: Changed, Old: "a", New: []interface{}{"a"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.old, tt.new, tt.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeKindString(t *testing.T) {
+	for kind, want := range map[ChangeKind]string{Added: "added", Removed: "removed", Changed: "changed", 0: "unknown"} {
+		if got := kind.String(); got != want {
+			t.Errorf("ChangeKind(%d).String() = %q; want %q", kind, got, want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Package dotenv decodes .env files into trees ask can query.
+//
+// Keys are kept as written, except that dots create nested maps, so
+// "database.pool.max=10" is found at "database.pool.max". Unquoted values
+// are typed like in the other loaders: true and false become bool,
+// integers int64 and decimals float64. Quoted values always stay strings.
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lukaszraczylo/ask"
+	"github.com/lukaszraczylo/ask/internal/scalar"
+)
+
+// Decode parses a .env document. Lines may start with "export". Single
+// quoted values are literal, double quoted values understand \n, \t, \",
+// \\ and may span lines. ${NAME} and $NAME in unquoted and double quoted
+// values expand to earlier keys of the same file; unknown names expand to
+// an empty string. A repeated key keeps its last value.
+func Decode(data []byte) (map[string]any, error) {
+	p := &parser{src: string(data), line: 1, vars: map[string]string{}}
+	flat := make(map[string]any)
+	for {
+		key, value, ok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		flat[key] = value
+	}
+	doc, err := ask.Unflatten(flat)
+	if err != nil {
+		return nil, fmt.Errorf("dotenv: %w", err)
+	}
+	return doc, nil
+}
+
+type parser struct {
+	src  string
+	pos  int
+	line int
+	vars map[string]string // raw values for expansion
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("dotenv: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// next returns the following assignment, ok is false at the end of input.
+func (p *parser) next() (key string, value any, ok bool, err error) {
+	for p.pos < len(p.src) {
+		lineEnd := strings.IndexByte(p.src[p.pos:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(p.src) - p.pos
+		}
+		text := strings.TrimSpace(p.src[p.pos : p.pos+lineEnd])
+		if text == "" || text[0] == '#' {
+			p.advance(lineEnd + 1)
+			continue
+		}
+
+		// Skip leading whitespace and "export" to find the key.
+		p.pos += strings.Index(p.src[p.pos:], text)
+		if strings.HasPrefix(text, "export ") {
+			p.pos += len("export ")
+			text = strings.TrimSpace(text[len("export "):])
+			p.pos += strings.Index(p.src[p.pos:], text)
+		}
+		eq := strings.IndexByte(text, '=')
+		if eq <= 0 {
+			return "", nil, false, p.errorf("expected KEY=value")
+		}
+		key = strings.TrimSpace(text[:eq])
+		p.pos += eq + 1
+		for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+			p.pos++
+		}
+
+		var raw string
+		quoted := true
+		switch {
+		case p.pos < len(p.src) && p.src[p.pos] == '\'':
+			raw, err = p.single()
+		case p.pos < len(p.src) && p.src[p.pos] == '"':
+			raw, err = p.double()
+		default:
+			quoted = false
+			raw = p.unquoted()
+		}
+		if err != nil {
+			return "", nil, false, err
+		}
+		p.vars[key] = raw
+		if quoted {
+			return key, raw, true, nil
+		}
+		return key, scalar.Infer(raw), true, nil
+	}
+	return "", nil, false, nil
+}
+
+func (p *parser) advance(n int) {
+	if p.pos+n > len(p.src) {
+		n = len(p.src) - p.pos
+	}
+	p.line += strings.Count(p.src[p.pos:p.pos+n], "\n")
+	p.pos += n
+}
+
+// rest consumes the remainder of the line after a quoted value, allowing
+// only a comment.
+func (p *parser) rest() error {
+	end := strings.IndexByte(p.src[p.pos:], '\n')
+	if end < 0 {
+		end = len(p.src) - p.pos
+	}
+	tail := strings.TrimSpace(p.src[p.pos : p.pos+end])
+	if tail != "" && tail[0] != '#' {
+		return p.errorf("unexpected %q after quoted value", tail)
+	}
+	p.advance(end + 1)
+	return nil
+}
+
+func (p *parser) single() (string, error) {
+	end := strings.IndexByte(p.src[p.pos+1:], '\'')
+	if end < 0 {
+		return "", p.errorf("unterminated single quoted value")
+	}
+	value := p.src[p.pos+1 : p.pos+1+end]
+	p.advance(end + 2)
+	return value, p.rest()
+}
+
+func (p *parser) double() (string, error) {
+	var b strings.Builder
+	start := p.line
+	i := p.pos + 1
+	for ; i < len(p.src); i++ {
+		c := p.src[i]
+		if c == '"' {
+			break
+		}
+		if c == '\\' && i+1 < len(p.src) {
+			i++
+			switch p.src[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteByte(p.src[i])
+			}
+			continue
+		}
+		b.WriteByte(c)
+	}
+	if i >= len(p.src) {
+		p.line = start
+		return "", p.errorf("unterminated double quoted value")
+	}
+	p.advance(i + 1 - p.pos)
+	return p.expand(b.String()), p.rest()
+}
+
+func (p *parser) unquoted() string {
+	end := strings.IndexByte(p.src[p.pos:], '\n')
+	if end < 0 {
+		end = len(p.src) - p.pos
+	}
+	raw := p.src[p.pos : p.pos+end]
+	p.advance(end + 1)
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = raw[:i]
+	}
+	return p.expand(strings.TrimSpace(raw))
+}
+
+// expand replaces ${NAME} and $NAME with earlier values.
+func (p *parser) expand(s string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		if s[i+1] == '{' {
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				b.WriteString(s[i:])
+				break
+			}
+			b.WriteString(p.vars[s[i+2:i+end]])
+			i += end
+			continue
+		}
+		j := i + 1
+		for j < len(s) && isNameChar(s[j]) {
+			j++
+		}
+		if j == i+1 {
+			b.WriteByte('$')
+			continue
+		}
+		b.WriteString(p.vars[s[i+1:j]])
+		i = j - 1
+	}
+	return b.String()
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,78 @@
+package dotenv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lukaszraczylo/ask"
+)
+
+const env = `# application settings
+APP_NAME=service
+export DEBUG=true
+PORT = 8080
+RATIO=0.5
+  INDENTED=yes # trailing comment
+GREETING="Hello\n\"World\""
+LITERAL='no $APP_NAME \n expansion'
+URL=http://${APP_NAME}:$PORT/path
+MULTI="line one
+line two"
+QUOTED_NUMBER="42"
+EMPTY=
+database.pool.max=10
+`
+
+func TestDecode(t *testing.T) {
+	doc, err := Decode([]byte(env))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{path: "APP_NAME", want: "service"},
+		{path: "DEBUG", want: true},
+		{path: "PORT", want: int64(8080)},
+		{path: "RATIO", want: 0.5},
+		{path: "INDENTED", want: "yes"},
+		{path: "GREETING", want: "Hello\n\"World\""},
+		{path: "LITERAL", want: `no $APP_NAME \n expansion`},
+		{path: "URL", want: "http://service:8080/path"},
+		{path: "MULTI", want: "line one\nline two"},
+		{path: "QUOTED_NUMBER", want: "42"},
+		{path: "EMPTY", want: ""},
+		{path: "database.pool.max", want: int64(10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := ask.For(doc, tt.path).Value(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("For(%q) = (%#v); want (%#v)", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "Missing equals", src: "A=1\nJUST_A_KEY"},
+		{name: "Unterminated double quote", src: `A="open`},
+		{name: "Unterminated single quote", src: `A='open`},
+		{name: "Text after quote", src: `A="x" y`},
+		{name: "Conflicting nesting", src: "a=1\na.b=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode([]byte(tt.src)); err == nil {
+				t.Errorf("Decode(%q) error = nil; want error", tt.src)
+			}
+		})
+	}
+}
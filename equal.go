@@ -0,0 +1,22 @@
+package ask
+
+// Equal reports whether two documents hold the same data. Unlike
+// reflect.DeepEqual, numbers compare by value the way the Int and Float
+// accessors convert them, so float64(3) from encoding/json equals the
+// literal 3, and typed maps and slices such as map[string]string or
+// []string equal their map[string]any and []any counterparts.
+//
+// The Diff options apply: IgnorePaths skips paths, and SlicesAsSets or
+// MatchSliceBy make the order of slice elements irrelevant.
+func Equal(a, b any, opts ...CompareOption) bool {
+	c := newCompareConfig(append([]CompareOption{LooseNumbers()}, opts...))
+	c.firstOnly = true
+	var changes []Change
+	c.diff(nil, a, b, &changes)
+	return len(changes) == 0
+}
+
+// Equals reports whether the answer holds the same data as v, see Equal.
+func (a *Answer) Equals(v any, opts ...CompareOption) bool {
+	return Equal(a.value, v, opts...)
+}
@@ -0,0 +1,64 @@
+package ask
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a    interface{}
+		b    interface{}
+		opts []CompareOption
+		want bool
+	}{
+		{name: "Float and int", a: float64(3), b: 3, want: true},
+		{name: "Different numbers", a: float64(3.5), b: 3, want: false},
+		{name: "Unsigned and signed", a: uint8(7), b: int64(7), want: true},
+		{name: "Large integers stay exact", a: int64(1<<62 + 1), b: uint64(1<<62 + 1), want: true},
+		{name: "Number and string", a: 1, b: "1", want: false},
+		{name: "Nil values", a: nil, b: nil, want: true},
+		{
+			name: "Typed map and slice",
+			a:    map[string][]string{"tags": {"a", "b"}},
+			b:    map[string]interface{}{"tags": []interface{}{"a", "b"}},
+			want: true,
+		},
+		{
+			name: "Decoded JSON and Go literal",
+			a:    map[string]interface{}{"port": float64(8080), "hosts": []interface{}{"a"}},
+			b:    map[string]interface{}{"port": 8080, "hosts": []string{"a"}},
+			want: true,
+		},
+		{name: "Missing key", a: map[string]interface{}{"a": 1}, b: map[string]interface{}{}, want: false},
+		{name: "Slice order matters", a: []int{1, 2}, b: []int{2, 1}, want: false},
+		{name: "Slice order ignored", a: []int{1, 2, 2}, b: []float64{2, 1, 2}, opts: []CompareOption{SlicesAsSets()}, want: true},
+		{name: "Sets keep multiplicity", a: []int{1, 2, 2}, b: []int{1, 1, 2}, opts: []CompareOption{SlicesAsSets()}, want: false},
+		{
+			name: "Ignored paths",
+			a:    map[string]interface{}{"id": 1, "meta": map[string]interface{}{"at": "mon"}},
+			b:    map[string]interface{}{"id": 1, "meta": map[string]interface{}{"at": "tue"}},
+			opts: []CompareOption{IgnorePaths("meta.at")},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.a, tt.b, tt.opts...); got != tt.want {
+				t.Errorf("Equal(%v, %v) = %t; want %t", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnswerEquals(t *testing.T) {
+	source := map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": float64(100)}}}
+	if !For(source, "a[0].b").Equals(100) {
+		t.Errorf("Equals(100) = false; want true")
+	}
+	if !For(source, "a[0]").Equals(map[string]int{"b": 100}) {
+		t.Errorf("Equals(map[string]int) = false; want true")
+	}
+	if For(source, "a[1]").Equals(0) {
+		t.Errorf("Equals(0) on a missing value = true; want false")
+	}
+}
@@ -0,0 +1,761 @@
+package ask
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExprFunc is a function callable from expressions. It receives the
+// evaluated arguments.
+type ExprFunc func(args ...any) (any, error)
+
+type exprConfig struct {
+	funcs map[string]ExprFunc
+}
+
+// ExprOption configures CompileExpr and Eval.
+type ExprOption func(*exprConfig)
+
+// WithFunc makes fn callable as name(...) in expressions. The built-in
+// functions len, lower and upper can be replaced the same way.
+func WithFunc(name string, fn ExprFunc) ExprOption {
+	return func(c *exprConfig) {
+		c.funcs[name] = fn
+	}
+}
+
+// Expr is a compiled expression, safe for concurrent use.
+type Expr struct {
+	src  string
+	root exprNode
+}
+
+// Eval compiles expr and evaluates it against source, see CompileExpr.
+func Eval(expr string, source any, opts ...ExprOption) (*Answer, error) {
+	e, err := CompileExpr(expr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return e.Eval(source)
+}
+
+// CompileExpr parses an expression such as
+//
+//	user.country in ['PL', 'DE'] && len(user.roles) > 0 && !user.banned
+//
+// Identifiers are paths resolved with For against the evaluated document,
+// so user.roles[0] works as well; a missing path is null. The language has
+// number, string ('single' or "double" quoted), true, false, null and
+// [list] literals and, from lowest to highest precedence:
+//
+//	||  &&  == !=  < <= > >= in matches  + -  * / %  ! and unary -
+//
+// == and != compare like Equal, so 1 == 1.0. + adds numbers or joins
+// strings. "x in list" tests membership, "key in map" a key and "s in
+// text" a substring. "s matches 're'" applies a regular expression.
+// && and || short-circuit; null, false, 0, "" and empty lists and maps
+// count as false. Functions are called as name(args) and come from the
+// built-in len, lower and upper or from WithFunc.
+func CompileExpr(expr string, opts ...ExprOption) (*Expr, error) {
+	c := &exprConfig{funcs: map[string]ExprFunc{
+		"len":   exprLen,
+		"lower": exprLower,
+		"upper": exprUpper,
+	}}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	p := &exprParser{lex: exprLexer{src: expr}, config: c}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	root, err := p.parse(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return &Expr{src: expr, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval evaluates the expression against source.
+func (e *Expr) Eval(source any) (*Answer, error) {
+	value, err := e.root.eval(source)
+	if err != nil {
+		return nil, fmt.Errorf("ask: eval %q: %w", e.src, err)
+	}
+	return &Answer{value: value}, nil
+}
+
+// Lexer.
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokLiteral
+	tokString
+	tokIdent
+	tokOp
+)
+
+type exprToken struct {
+	kind  tokenKind
+	text  string
+	value any
+	pos   int
+}
+
+func (t exprToken) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+type exprLexer struct {
+	src string
+	pos int
+}
+
+// operators lists multi-character operators before their prefixes.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "[", "]", ","}
+
+func (l *exprLexer) next() (exprToken, error) {
+	for l.pos < len(l.src) && (l.src[l.pos] == ' ' || l.src[l.pos] == '\t' || l.src[l.pos] == '\n' || l.src[l.pos] == '\r') {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return exprToken{kind: tokEOF, pos: start}, nil
+	}
+
+	ch := l.src[l.pos]
+	switch {
+	case ch >= '0' && ch <= '9':
+		for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || l.src[l.pos] == '.' || l.src[l.pos] == '_') {
+			l.pos++
+		}
+		if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+			end := l.pos + 1
+			if end < len(l.src) && (l.src[end] == '+' || l.src[end] == '-') {
+				end++
+			}
+			if end < len(l.src) && isDigit(l.src[end]) {
+				for end < len(l.src) && isDigit(l.src[end]) {
+					end++
+				}
+				l.pos = end
+			}
+		}
+		text := l.src[start:l.pos]
+		f, err := strconv.ParseFloat(strings.ReplaceAll(text, "_", ""), 64)
+		if err != nil {
+			return exprToken{}, fmt.Errorf("invalid number %q at offset %d", text, start)
+		}
+		return exprToken{kind: tokLiteral, text: text, value: f, pos: start}, nil
+	case ch == '\'' || ch == '"':
+		return l.string(ch)
+	case ch == '_' || ch == '$' || isLetter(l.src[l.pos:]):
+		return l.ident(), nil
+	}
+	for _, op := range operators {
+		if strings.HasPrefix(l.src[l.pos:], op) {
+			l.pos += len(op)
+			return exprToken{kind: tokOp, text: op, pos: start}, nil
+		}
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return exprToken{}, fmt.Errorf("unexpected character %q at offset %d", r, start)
+}
+
+func (l *exprLexer) string(quote byte) (exprToken, error) {
+	start := l.pos
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		ch := l.src[l.pos]
+		switch {
+		case ch == quote:
+			l.pos++
+			return exprToken{kind: tokString, text: l.src[start:l.pos], value: b.String(), pos: start}, nil
+		case ch == '\\' && l.pos+1 < len(l.src):
+			l.pos++
+			switch esc := l.src[l.pos]; esc {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(esc)
+			}
+		default:
+			b.WriteByte(ch)
+		}
+		l.pos++
+	}
+	return exprToken{}, fmt.Errorf("unterminated string at offset %d", start)
+}
+
+// ident reads a path such as user.roles[0].name. Brackets directly after
+// a name with a number inside belong to the path; anything else in
+// brackets is left for the parser.
+func (l *exprLexer) ident() exprToken {
+	start := l.pos
+	for l.pos < len(l.src) {
+		rest := l.src[l.pos:]
+		switch {
+		case rest[0] == '_' || rest[0] == '$' || isDigit(rest[0]) || isLetter(rest):
+			_, size := utf8.DecodeRuneInString(rest)
+			l.pos += size
+		case rest[0] == '.' && len(rest) > 1 && (rest[1] == '_' || isLetter(rest[1:])):
+			l.pos++
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 2 || strings.Trim(rest[1:end], "0123456789") != "" {
+				return l.identToken(start)
+			}
+			l.pos += end + 1
+		default:
+			return l.identToken(start)
+		}
+	}
+	return l.identToken(start)
+}
+
+func (l *exprLexer) identToken(start int) exprToken {
+	text := l.src[start:l.pos]
+	switch text {
+	case "true":
+		return exprToken{kind: tokLiteral, text: text, value: true, pos: start}
+	case "false":
+		return exprToken{kind: tokLiteral, text: text, value: false, pos: start}
+	case "null", "nil":
+		return exprToken{kind: tokLiteral, text: text, value: nil, pos: start}
+	case "in", "matches":
+		return exprToken{kind: tokOp, text: text, pos: start}
+	}
+	return exprToken{kind: tokIdent, text: text, pos: start}
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isLetter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLetter(r)
+}
+
+// Parser.
+
+type exprParser struct {
+	lex    exprLexer
+	tok    exprToken
+	config *exprConfig
+}
+
+func (p *exprParser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return fmt.Errorf("ask: expression %q: %w", p.lex.src, err)
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("ask: expression %q: offset %d: %s", p.lex.src, p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+// precedence returns the binding power of a binary operator, 0 for
+// anything that does not continue an expression.
+func precedence(tok exprToken) int {
+	if tok.kind != tokOp {
+		return 0
+	}
+	switch tok.text {
+	case "||":
+		return 1
+	case "&&":
+		return 2
+	case "==", "!=":
+		return 3
+	case "<", "<=", ">", ">=", "in", "matches":
+		return 4
+	case "+", "-":
+		return 5
+	case "*", "/", "%":
+		return 6
+	}
+	return 0
+}
+
+// parse reads an expression whose operators bind tighter than minPrec.
+func (p *exprParser) parse(minPrec int) (exprNode, error) {
+	left, err := p.prefix()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		prec := precedence(p.tok)
+		if prec <= minPrec {
+			return left, nil
+		}
+		op := p.tok.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parse(prec)
+		if err != nil {
+			return nil, err
+		}
+		if left, err = newBinary(op, left, right); err != nil {
+			return nil, p.errorf("%v", err)
+		}
+	}
+}
+
+// unaryPrecedence binds ! and unary - tighter than every binary operator.
+const unaryPrecedence = 7
+
+func (p *exprParser) prefix() (exprNode, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokEOF:
+		return nil, p.errorf("unexpected end of expression")
+	case tokLiteral, tokString:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return &literalNode{value: tok.value}, nil
+	case tokIdent:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokOp && p.tok.text == "(" {
+			return p.call(tok)
+		}
+		return &pathNode{path: tok.text}, nil
+	}
+
+	switch tok.text {
+	case "!", "-":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.parse(unaryPrecedence)
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: tok.text, x: x}, nil
+	case "(":
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		x, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+	case "[":
+		items, err := p.list("]")
+		if err != nil {
+			return nil, err
+		}
+		return &listNode{items: items}, nil
+	}
+	return nil, p.errorf("unexpected %s", tok)
+}
+
+func (p *exprParser) call(name exprToken) (exprNode, error) {
+	fn, ok := p.config.funcs[name.text]
+	if !ok {
+		p.tok = name
+		return nil, p.errorf("unknown function %q", name.text)
+	}
+	args, err := p.list(")")
+	if err != nil {
+		return nil, err
+	}
+	return &callNode{name: name.text, fn: fn, args: args}, nil
+}
+
+// list reads comma separated expressions after an opening bracket up to
+// and including end.
+func (p *exprParser) list(end string) ([]exprNode, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var items []exprNode
+	for !(p.tok.kind == tokOp && p.tok.text == end) {
+		if len(items) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		item, err := p.parse(0)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, p.advance()
+}
+
+func (p *exprParser) expect(op string) error {
+	if p.tok.kind != tokOp || p.tok.text != op {
+		return p.errorf("expected %q, found %s", op, p.tok)
+	}
+	return p.advance()
+}
+
+// Evaluation.
+
+type exprNode interface {
+	eval(source any) (any, error)
+}
+
+type literalNode struct {
+	value any
+}
+
+func (n *literalNode) eval(any) (any, error) {
+	return n.value, nil
+}
+
+type pathNode struct {
+	path string
+}
+
+func (n *pathNode) eval(source any) (any, error) {
+	if n.path == "$" {
+		return source, nil
+	}
+	return For(source, n.path).Value(), nil
+}
+
+type listNode struct {
+	items []exprNode
+}
+
+func (n *listNode) eval(source any) (any, error) {
+	values := make([]any, len(n.items))
+	for i, item := range n.items {
+		v, err := item.eval(source)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+type callNode struct {
+	name string
+	fn   ExprFunc
+	args []exprNode
+}
+
+func (n *callNode) eval(source any) (any, error) {
+	args := make([]any, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(source)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	v, err := n.fn(args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return v, nil
+}
+
+type unaryNode struct {
+	op string
+	x  exprNode
+}
+
+func (n *unaryNode) eval(source any) (any, error) {
+	v, err := n.x.eval(source)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		return !truthy(v), nil
+	}
+	f, ok := number(v)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate %s", describeValue(v))
+	}
+	return -f, nil
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+	pattern     *regexp.Regexp // precompiled for matches with a literal pattern
+}
+
+func newBinary(op string, left, right exprNode) (exprNode, error) {
+	n := &binaryNode{op: op, left: left, right: right}
+	if lit, ok := right.(*literalNode); ok && op == "matches" {
+		s, ok := lit.value.(string)
+		if !ok {
+			return nil, fmt.Errorf("matches needs a string pattern")
+		}
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return nil, err
+		}
+		n.pattern = re
+	}
+	return n, nil
+}
+
+func (n *binaryNode) eval(source any) (any, error) {
+	left, err := n.left.eval(source)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(source)
+		return truthy(right), err
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(source)
+		return truthy(right), err
+	}
+
+	right, err := n.right.eval(source)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return Equal(left, right), nil
+	case "!=":
+		return !Equal(left, right), nil
+	case "in":
+		return contains(right, left), nil
+	case "matches":
+		s, ok := left.(string)
+		if !ok {
+			return false, nil
+		}
+		re := n.pattern
+		if re == nil {
+			expr, ok := right.(string)
+			if !ok {
+				return nil, fmt.Errorf("matches needs a string pattern, got %s", describeValue(right))
+			}
+			if re, err = regexp.Compile(expr); err != nil {
+				return nil, err
+			}
+		}
+		return re.MatchString(s), nil
+	case "<", "<=", ">", ">=":
+		return compare(n.op, left, right)
+	}
+	return arithmetic(n.op, left, right)
+}
+
+func compare(op string, left, right any) (any, error) {
+	var cmp int
+	lf, lok := number(left)
+	rf, rok := number(right)
+	ls, lsok := left.(string)
+	rs, rsok := right.(string)
+	switch {
+	case lok && rok:
+		cmp = compareNumbers(left, right, lf, rf)
+	case lsok && rsok:
+		cmp = strings.Compare(ls, rs)
+	default:
+		return nil, fmt.Errorf("cannot compare %s %s %s", describeValue(left), op, describeValue(right))
+	}
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+	return cmp >= 0, nil
+}
+
+// compareNumbers orders two numbers, comparing integers exactly like
+// numbersEqual and everything else by the float values lf and rf.
+func compareNumbers(left, right any, lf, rf float64) int {
+	if li, ok := exactInt(left); ok {
+		if ri, ok := exactInt(right); ok {
+			return cmpOrdered(li, ri)
+		}
+	}
+	lu, lok := left.(uint64)
+	ru, rok := right.(uint64)
+	if lok && rok {
+		return cmpOrdered(lu, ru)
+	}
+	return cmpOrdered(lf, rf)
+}
+
+func cmpOrdered[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func arithmetic(op string, left, right any) (any, error) {
+	if op == "+" {
+		ls, lok := left.(string)
+		rs, rok := right.(string)
+		if lok && rok {
+			return ls + rs, nil
+		}
+	}
+	lf, lok := number(left)
+	rf, rok := number(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("cannot compute %s %s %s", describeValue(left), op, describeValue(right))
+	}
+	switch op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	}
+	if rf == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	if op == "/" {
+		return lf / rf, nil
+	}
+	return math.Mod(lf, rf), nil
+}
+
+// contains implements "needle in haystack".
+func contains(haystack, needle any) bool {
+	if s, ok := haystack.(string); ok {
+		n, ok := needle.(string)
+		return ok && strings.Contains(s, n)
+	}
+	if m, ok := asMap(haystack); ok {
+		key, ok := needle.(string)
+		if !ok {
+			return false
+		}
+		_, found := m[key]
+		return found
+	}
+	if list, ok := asSlice(haystack); ok {
+		for _, item := range list {
+			if Equal(item, needle) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// number widens numeric values like Answer.Float.
+func number(v any) (float64, bool) {
+	if _, ok := v.(bool); ok {
+		return 0, false
+	}
+	return (&Answer{value: v}).Float(0)
+}
+
+// truthy decides how a value counts in && || and !.
+func truthy(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case string:
+		return val != ""
+	}
+	if f, ok := number(v); ok {
+		return f != 0
+	}
+	if m, ok := asMap(v); ok {
+		return len(m) > 0
+	}
+	if s, ok := asSlice(v); ok {
+		return len(s) > 0
+	}
+	return true
+}
+
+func describeValue(v any) string {
+	if v == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func exprLen(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+	}
+	switch v := args[0].(type) {
+	case nil:
+		return float64(0), nil
+	case string:
+		return float64(utf8.RuneCountInString(v)), nil
+	}
+	if m, ok := asMap(args[0]); ok {
+		return float64(len(m)), nil
+	}
+	if s, ok := asSlice(args[0]); ok {
+		return float64(len(s)), nil
+	}
+	return nil, fmt.Errorf("cannot take the length of %s", describeValue(args[0]))
+}
+
+func exprLower(args ...any) (any, error) {
+	return mapString(args, strings.ToLower)
+}
+
+func exprUpper(args ...any) (any, error) {
+	return mapString(args, strings.ToUpper)
+}
+
+func mapString(args []any, fn func(string) string) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+	}
+	s, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a string, got %s", describeValue(args[0]))
+	}
+	return fn(s), nil
+}
@@ -0,0 +1,173 @@
+package ask
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const flagContext = `{
+	"user": {
+		"country": "PL",
+		"roles": ["admin", "dev"],
+		"banned": false,
+		"age": 31,
+		"email": "ann@example.com",
+		"plan": {"name": "pro", "seats": 5}
+	}
+}`
+
+func TestEval(t *testing.T) {
+	doc := decodeJSON(t, flagContext)
+	tests := []struct {
+		name string
+		expr string
+		want interface{}
+	}{
+		{name: "Rule", expr: "user.country in ['PL', 'DE'] && len(user.roles) > 0 && !user.banned", want: true},
+		{name: "Precedence", expr: "1 + 2 * 3 - 4 / 2", want: 5.0},
+		{name: "Parentheses", expr: "(1 + 2) * 3", want: 9.0},
+		{name: "Modulo", expr: "user.age % 10", want: 1.0},
+		{name: "Fractional modulo", expr: "5 % 0.5 + 5.5 % 2", want: 1.5},
+		{name: "Large modulo", expr: "1e20 % 7", want: 2.0},
+		{name: "Exponent", expr: "1e3 + 2.5E-1 + 1e+1", want: 1010.25},
+		{name: "Unary minus", expr: "-user.plan.seats + 1", want: -4.0},
+		{name: "String concat", expr: `user.plan.name + "-" + user.country`, want: "pro-PL"},
+		{name: "Loose equality", expr: "user.age == 31.0", want: true},
+		{name: "Not equal", expr: "user.plan.name != 'pro'", want: false},
+		{name: "String compare", expr: "user.country < 'US'", want: true},
+		{name: "Index path", expr: "user.roles[1] == 'dev'", want: true},
+		{name: "Missing is null", expr: "user.nickname == null", want: true},
+		{name: "Missing is falsy", expr: "!user.deleted", want: true},
+		{name: "In map", expr: "'seats' in user.plan", want: true},
+		{name: "In string", expr: "'example' in user.email", want: true},
+		{name: "Not in list", expr: "!('ops' in user.roles)", want: true},
+		{name: "Matches", expr: `user.email matches '^[a-z]+@example\\.com$'`, want: true},
+		{name: "Matches non-string", expr: "user.age matches '3'", want: false},
+		{name: "Short circuit and", expr: "user.banned && 1 / 0", want: false},
+		{name: "Short circuit or", expr: "user.age > 18 || 1 / 0", want: true},
+		{name: "Truthy values", expr: "user.roles && user.plan.seats && user.country", want: true},
+		{name: "Builtins", expr: "upper('pl') + lower('AB') == 'PLab' && len('żółw') == 4", want: true},
+		{name: "List literal", expr: "[1, user.country]", want: []interface{}{1.0, "PL"}},
+		{name: "Root", expr: "len($)", want: 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Eval(tt.expr, doc)
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Value(), tt.want) {
+				t.Errorf("Eval() = %#v; want %#v", got.Value(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalLargeIntegers(t *testing.T) {
+	doc := map[string]interface{}{
+		"a": int64(9007199254740993),
+		"b": int64(9007199254740992),
+		"u": uint64(18446744073709551615),
+		"v": uint64(18446744073709551614),
+	}
+	for expr, want := range map[string]bool{
+		"a > b":  true,
+		"a >= b": true,
+		"a < b":  false,
+		"a == b": false,
+		"u > v":  true,
+		"v < u":  true,
+		"a < u":  true,
+	} {
+		got, err := Eval(expr, doc)
+		if err != nil {
+			t.Fatalf("Eval(%q) error = %v", expr, err)
+		}
+		if got.Value() != want {
+			t.Errorf("Eval(%q) = %v; want %v", expr, got.Value(), want)
+		}
+	}
+}
+
+func TestExprFunctions(t *testing.T) {
+	doc := decodeJSON(t, flagContext)
+	hasRole := WithFunc("hasRole", func(args ...interface{}) (interface{}, error) {
+		if len(args) != 2 {
+			return nil, errors.New("expected 2 arguments")
+		}
+		return contains(args[0], args[1]), nil
+	})
+
+	e, err := CompileExpr("hasRole(user.roles, 'admin') && len(lower(user.country)) == 2", hasRole)
+	if err != nil {
+		t.Fatalf("CompileExpr() error = %v", err)
+	}
+	if got, ok := mustEval(t, e, doc).Bool(false); !ok || !got {
+		t.Errorf("Eval() = %v; want true", got)
+	}
+	if e.String() != "hasRole(user.roles, 'admin') && len(lower(user.country)) == 2" {
+		t.Errorf("String() = %q", e.String())
+	}
+
+	// The compiled form is reusable across documents.
+	other := map[string]interface{}{"user": map[string]interface{}{"roles": []interface{}{"dev"}, "country": "DE"}}
+	if got, _ := mustEval(t, e, other).Bool(true); got {
+		t.Errorf("Eval() on other document = true; want false")
+	}
+
+	_, err = Eval("hasRole(user.roles)", doc, hasRole)
+	if err == nil || !strings.Contains(err.Error(), "hasRole: expected 2 arguments") {
+		t.Errorf("Eval() error = %v; want function error", err)
+	}
+}
+
+func TestExprErrors(t *testing.T) {
+	doc := decodeJSON(t, flagContext)
+	compileErrors := []string{
+		"",
+		"1 +",
+		"(1 + 2",
+		"[1, 2",
+		"user.age >",
+		"nope(1)",
+		"'open",
+		"1 # 2",
+		"1 2",
+		"1e",
+		"user.email matches '['",
+		"user.email matches 1",
+	}
+	for _, expr := range compileErrors {
+		if _, err := CompileExpr(expr); err == nil {
+			t.Errorf("CompileExpr(%q) error = nil; want error", expr)
+		}
+	}
+
+	evalErrors := []string{
+		"user.age / 0",
+		"user.age % 0",
+		"user.roles + 1",
+		"user.country > 1",
+		"-user.country",
+		"len(user.age)",
+		"upper(1)",
+		"user.email matches user.age",
+	}
+	for _, expr := range evalErrors {
+		if _, err := Eval(expr, doc); err == nil {
+			t.Errorf("Eval(%q) error = nil; want error", expr)
+		}
+	}
+}
+
+func mustEval(t *testing.T, e *Expr, doc interface{}) *Answer {
+	t.Helper()
+	got, err := e.Eval(doc)
+	if err != nil {
+		t.Fatalf("Eval() error = %v", err)
+	}
+	return got
+}
@@ -0,0 +1,81 @@
+package ask
+
+import (
+	"strings"
+	"sync"
+)
+
+var extractCache sync.Map // joined paths -> *extractNode
+
+// extractNode is a node of the prefix trie built from a set of paths. Paths
+// sharing a prefix share nodes, so every shared prefix is walked only once.
+type extractNode struct {
+	token    string
+	targets  []int // indices of the paths that end at this node
+	children []*extractNode
+}
+
+// Extract selects many paths from source in a single traversal and returns
+// one answer per path, in the order the paths were given.
+func Extract(source any, paths ...string) []*Answer {
+	results := make([]*Answer, len(paths))
+	if len(paths) == 0 {
+		return results
+	}
+	compileExtract(paths).walk(source, results)
+	for i, res := range results {
+		if res == nil {
+			results[i] = &Answer{}
+		}
+	}
+	return results
+}
+
+// ExtractMap does the same thing as Extract but returns answers keyed by path.
+func ExtractMap(source any, paths ...string) map[string]*Answer {
+	results := make(map[string]*Answer, len(paths))
+	for i, res := range Extract(source, paths...) {
+		results[paths[i]] = res
+	}
+	return results
+}
+
+// compileExtract returns the cached trie for paths, building it on first use.
+func compileExtract(paths []string) *extractNode {
+	key := strings.Join(paths, "\x00")
+	if root, ok := extractCache.Load(key); ok {
+		return root.(*extractNode)
+	}
+	root := &extractNode{}
+	for i, path := range paths {
+		node := root
+		for _, token := range splitPath(path) {
+			node = node.child(token)
+		}
+		node.targets = append(node.targets, i)
+	}
+	extractCache.Store(key, root)
+	return root
+}
+
+func (n *extractNode) child(token string) *extractNode {
+	for _, c := range n.children {
+		if c.token == token {
+			return c
+		}
+	}
+	c := &extractNode{token: token}
+	n.children = append(n.children, c)
+	return c
+}
+
+func (n *extractNode) walk(value any, results []*Answer) {
+	for _, i := range n.targets {
+		results[i] = &Answer{value: value}
+	}
+	for _, c := range n.children {
+		if next := step(value, c.token); next != nil {
+			c.walk(next, results)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package ask
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	source := map[string]interface{}{
+		"payload": map[string]interface{}{
+			"user": map[string]interface{}{
+				"id":   42,
+				"name": "ann",
+				"tags": []interface{}{"a", "b"},
+			},
+		},
+		"meta": []interface{}{map[string]interface{}{"ts": 1.5}},
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+		want  []interface{}
+	}{
+		{
+			name:  "Shared prefixes",
+			paths: []string{"payload.user.id", "payload.user.name", "payload.user.tags[1]"},
+			want:  []interface{}{42, "ann", "b"},
+		},
+		{
+			name:  "Missing paths yield empty answers",
+			paths: []string{"payload.user.missing", "meta[3].ts", "meta[0].ts"},
+			want:  []interface{}{nil, nil, 1.5},
+		},
+		{
+			name:  "Duplicate and equivalent paths",
+			paths: []string{"payload.user.id", " payload . user.id ", "payload.user.id"},
+			want:  []interface{}{42, 42, 42},
+		},
+		{
+			name:  "Empty path returns source",
+			paths: []string{""},
+			want:  []interface{}{source},
+		},
+		{
+			name: "No paths",
+			want: []interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answers := Extract(source, tt.paths...)
+			got := make([]interface{}, len(answers))
+			for i, a := range answers {
+				got[i] = a.Value()
+				if want := For(source, tt.paths[i]).Value(); !reflect.DeepEqual(got[i], want) {
+					t.Errorf("Extract()[%d] = (%v); For() = (%v)", i, got[i], want)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() = (%v); want (%v)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractMap(t *testing.T) {
+	source := map[string]interface{}{"a": map[string]interface{}{"b": 1, "c": 2}}
+	got := ExtractMap(source, "a.b", "a.c", "a.d")
+	if v, _ := got["a.b"].Int(0); v != 1 {
+		t.Errorf(`ExtractMap()["a.b"] = %d; want 1`, v)
+	}
+	if v, _ := got["a.c"].Int(0); v != 2 {
+		t.Errorf(`ExtractMap()["a.c"] = %d; want 2`, v)
+	}
+	if got["a.d"].Exists() {
+		t.Errorf(`ExtractMap()["a.d"] exists; want missing`)
+	}
+}
@@ -0,0 +1,311 @@
+package ask
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxUnflattenIndex guards Unflatten against keys such as "a[999999999]"
+// that would otherwise allocate huge, mostly empty slices.
+const maxUnflattenIndex = 1 << 16
+
+// ArrayNotation selects how slice indices are written in flattened keys.
+type ArrayNotation int
+
+const (
+	// BracketNotation writes indices as "a[0].b", the same form For accepts.
+	BracketNotation ArrayNotation = iota
+	// SeparatorNotation writes indices as plain segments, e.g. "a.0.b".
+	SeparatorNotation
+)
+
+type flattenConfig struct {
+	separator string
+	notation  ArrayNotation
+}
+
+// FlattenOption configures Flatten and Unflatten.
+type FlattenOption func(*flattenConfig)
+
+// WithSeparator sets the string placed between map keys, "." by default.
+func WithSeparator(sep string) FlattenOption {
+	return func(c *flattenConfig) {
+		if sep != "" {
+			c.separator = sep
+		}
+	}
+}
+
+// WithArrayNotation sets how slice indices are written, BracketNotation by default.
+func WithArrayNotation(n ArrayNotation) FlattenOption {
+	return func(c *flattenConfig) {
+		c.notation = n
+	}
+}
+
+func newFlattenConfig(opts []FlattenOption) *flattenConfig {
+	c := &flattenConfig{separator: ".", notation: BracketNotation}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Flatten turns nested maps and slices into a single level map keyed by paths.
+// Empty maps and slices are kept as values so Unflatten can restore them.
+//
+// With default options the keys can be passed straight to For, as long as
+// no map key contains "." or "[". The path grammar has no escaping, so
+// Flatten(map[string]any{"a.b": 1}) yields the key "a.b", which For and
+// Unflatten read as "b" nested in "a". Should two paths end up as the same
+// key, as with {"a.b": 1, "a": {"b": 2}}, map keys are visited in sorted
+// order and the later one wins, so the result does not vary between runs.
+func Flatten(source any, opts ...FlattenOption) map[string]any {
+	c := newFlattenConfig(opts)
+	result := make(map[string]any)
+	c.flatten(result, "", source)
+	return result
+}
+
+func (c *flattenConfig) flatten(result map[string]any, prefix string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			c.emptyLeaf(result, prefix, v)
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			c.flatten(result, c.joinKey(prefix, k), v[k])
+		}
+		return
+	case []any:
+		if len(v) == 0 {
+			c.emptyLeaf(result, prefix, v)
+			return
+		}
+		for i, child := range v {
+			c.flatten(result, c.joinIndex(prefix, i), child)
+		}
+		return
+	}
+
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Map:
+		if val.Len() == 0 {
+			c.emptyLeaf(result, prefix, value)
+			return
+		}
+		entries := make(map[string]any, val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			entries[keyString(iter.Key().Interface())] = iter.Value().Interface()
+		}
+		c.flatten(result, prefix, entries)
+		return
+	case reflect.Slice, reflect.Array:
+		if val.Len() == 0 {
+			c.emptyLeaf(result, prefix, value)
+			return
+		}
+		for i := 0; i < val.Len(); i++ {
+			c.flatten(result, c.joinIndex(prefix, i), val.Index(i).Interface())
+		}
+		return
+	}
+	result[prefix] = value
+}
+
+// emptyLeaf records an empty map or slice, except at the root where it would
+// produce a key Unflatten cannot restore.
+func (c *flattenConfig) emptyLeaf(result map[string]any, prefix string, value any) {
+	if prefix != "" {
+		result[prefix] = value
+	}
+}
+
+func (c *flattenConfig) joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + c.separator + key
+}
+
+func (c *flattenConfig) joinIndex(prefix string, index int) string {
+	if c.notation == BracketNotation {
+		return prefix + "[" + strconv.Itoa(index) + "]"
+	}
+	return c.joinKey(prefix, strconv.Itoa(index))
+}
+
+// Unflatten rebuilds nested maps and slices from keys produced by Flatten.
+// It fails when two keys disagree about the shape of a node, for example
+// "a.b" and "a[0]", or when a key would place a slice at the root.
+func Unflatten(flat map[string]any, opts ...FlattenOption) (map[string]any, error) {
+	c := newFlattenConfig(opts)
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	// Sorting puts parents before their children, so conflicts are reported
+	// the same way regardless of map iteration order.
+	sort.Strings(keys)
+
+	result := make(map[string]any)
+	for _, key := range keys {
+		value := flat[key]
+		tokens := c.tokenize(key)
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("ask: cannot unflatten empty key")
+		}
+		if isIndexToken(tokens[0]) {
+			return nil, fmt.Errorf("ask: cannot unflatten %q: root must be a map", key)
+		}
+		if _, err := assign(result, tokens, value); err != nil {
+			return nil, fmt.Errorf("ask: cannot unflatten %q: %w", key, err)
+		}
+	}
+	return result, nil
+}
+
+// tokenize splits a flattened key into the tokens understood by assign.
+func (c *flattenConfig) tokenize(key string) []string {
+	if c.separator == "." && c.notation == BracketNotation {
+		return tokenizePath(key)
+	}
+	var tokens []string
+	for _, part := range strings.Split(key, c.separator) {
+		part = trimSpaceASCII(part)
+		if part == "" {
+			continue
+		}
+		if c.notation == SeparatorNotation {
+			if _, err := strconv.Atoi(part); err == nil {
+				tokens = append(tokens, "["+part+"]")
+				continue
+			}
+		}
+		tokens = append(tokens, splitBrackets(part)...)
+	}
+	return tokens
+}
+
+// splitBrackets separates trailing index tokens from a key, "a[0][1]" becomes
+// "a", "[0]", "[1]".
+func splitBrackets(part string) []string {
+	var tokens []string
+	for {
+		open := strings.IndexByte(part, '[')
+		if open < 0 || !strings.HasSuffix(part[open:], "]") {
+			break
+		}
+		end := strings.IndexByte(part[open:], ']') + open
+		if open > 0 {
+			tokens = append(tokens, part[:open])
+		}
+		tokens = append(tokens, part[open:end+1])
+		part = part[end+1:]
+	}
+	if part != "" {
+		tokens = append(tokens, part)
+	}
+	return tokens
+}
+
+// genericCopy copies the generic maps and slices in value, the containers
+// assign writes into, so that later assignments never write into the
+// caller's values. Other values are shared.
+func genericCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			out[k] = genericCopy(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = genericCopy(child)
+		}
+		return out
+	}
+	return value
+}
+
+func isIndexToken(token string) bool {
+	return len(token) >= 2 && token[0] == '[' && token[len(token)-1] == ']'
+}
+
+func tokenIndex(token string) (int, bool) {
+	if !isIndexToken(token) {
+		return 0, false
+	}
+	index, err := strconv.Atoi(strings.TrimSpace(token[1 : len(token)-1]))
+	if err != nil || index < 0 {
+		return 0, false
+	}
+	return index, true
+}
+
+// assign stores value at tokens inside container, creating map[string]any and
+// []any nodes on the way. It returns the possibly reallocated container.
+func assign(container any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		if container != nil {
+			return nil, fmt.Errorf("conflicting values")
+		}
+		return genericCopy(value), nil
+	}
+	token := tokens[0]
+	if isIndexToken(token) {
+		index, ok := tokenIndex(token)
+		if !ok {
+			return nil, fmt.Errorf("invalid index %s", token)
+		}
+		if index >= maxUnflattenIndex {
+			return nil, fmt.Errorf("index %d exceeds limit", index)
+		}
+		var s []any
+		switch c := container.(type) {
+		case nil:
+		case []any:
+			s = c
+		default:
+			return nil, fmt.Errorf("%s used on non-slice value", token)
+		}
+		for len(s) <= index {
+			s = append(s, nil)
+		}
+		child, err := assign(s[index], tokens[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		s[index] = child
+		return s, nil
+	}
+
+	var m map[string]any
+	switch c := container.(type) {
+	case nil:
+		m = make(map[string]any)
+	case map[string]any:
+		m = c
+	default:
+		return nil, fmt.Errorf("key %q used on non-map value", token)
+	}
+	child, err := assign(m[token], tokens[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	m[token] = child
+	return m, nil
+}
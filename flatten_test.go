@@ -0,0 +1,209 @@
+package ask
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	source := map[string]interface{}{
+		"a": []interface{}{
+			map[string]interface{}{
+				"b": 1,
+				"c": []int{2, 3},
+			},
+		},
+		"d":     "text",
+		"empty": map[string]interface{}{},
+		"none":  []interface{}{},
+	}
+
+	tests := []struct {
+		name string
+		opts []FlattenOption
+		want map[string]interface{}
+	}{
+		{
+			name: "Default notation",
+			want: map[string]interface{}{
+				"a[0].b":    1,
+				"a[0].c[0]": 2,
+				"a[0].c[1]": 3,
+				"d":         "text",
+				"empty":     map[string]interface{}{},
+				"none":      []interface{}{},
+			},
+		},
+		{
+			name: "Custom separator and separator notation",
+			opts: []FlattenOption{WithSeparator("_"), WithArrayNotation(SeparatorNotation)},
+			want: map[string]interface{}{
+				"a_0_b":   1,
+				"a_0_c_0": 2,
+				"a_0_c_1": 3,
+				"d":       "text",
+				"empty":   map[string]interface{}{},
+				"none":    []interface{}{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Flatten(source, tt.opts...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Flatten() = (%v); want (%v)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlattenKeysWorkWithFor(t *testing.T) {
+	source := map[string]interface{}{
+		"a": []interface{}{map[string]interface{}{"b": map[string]int{"c": 3}}},
+	}
+	for key, value := range Flatten(source) {
+		if got := For(source, key).Value(); !reflect.DeepEqual(got, value) {
+			t.Errorf("For(%q) = (%v); want (%v)", key, got, value)
+		}
+	}
+}
+
+func TestFlattenKeysWithSeparators(t *testing.T) {
+	// Keys are written unescaped, so a dotted key reads back as nesting.
+	flat := Flatten(map[string]interface{}{"a.b": 1, "c[0]": 2})
+	if want := map[string]interface{}{"a.b": 1, "c[0]": 2}; !reflect.DeepEqual(flat, want) {
+		t.Errorf("Flatten() = %v; want %v", flat, want)
+	}
+	got, err := Unflatten(flat)
+	if err != nil {
+		t.Fatalf("Unflatten() error = %v", err)
+	}
+	want := map[string]interface{}{"a": map[string]interface{}{"b": 1}, "c": []interface{}{2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unflatten(Flatten()) = %v; want %v", got, want)
+	}
+}
+
+func TestFlattenCollisionIsStable(t *testing.T) {
+	source := map[string]interface{}{"a.b": 1, "a": map[string]interface{}{"b": 2}, "c": map[string]int{"d": 3}}
+	for i := 0; i < 50; i++ {
+		got := Flatten(source)
+		if want := map[string]interface{}{"a.b": 1, "c.d": 3}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("Flatten() = %v; want %v", got, want)
+		}
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	tests := []struct {
+		name    string
+		flat    map[string]interface{}
+		opts    []FlattenOption
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "Nested maps and slices",
+			flat: map[string]interface{}{
+				"a[0].b":    1,
+				"a[0].c[1]": 3,
+				"a[0].c[0]": 2,
+				"d":         "text",
+			},
+			want: map[string]interface{}{
+				"a": []interface{}{
+					map[string]interface{}{
+						"b": 1,
+						"c": []interface{}{2, 3},
+					},
+				},
+				"d": "text",
+			},
+		},
+		{
+			name: "Separator notation",
+			flat: map[string]interface{}{
+				"a__0__b": true,
+			},
+			opts: []FlattenOption{WithSeparator("__"), WithArrayNotation(SeparatorNotation)},
+			want: map[string]interface{}{
+				"a": []interface{}{map[string]interface{}{"b": true}},
+			},
+		},
+		{
+			name: "Sparse indices are padded with nil",
+			flat: map[string]interface{}{"a[2]": 1},
+			want: map[string]interface{}{"a": []interface{}{nil, nil, 1}},
+		},
+		{
+			name:    "Conflicting shapes",
+			flat:    map[string]interface{}{"a.b": 1, "a[0]": 2},
+			wantErr: true,
+		},
+		{
+			name:    "Value and children at the same key",
+			flat:    map[string]interface{}{"a": 1, "a.b": 2},
+			wantErr: true,
+		},
+		{
+			name:    "Slice at root",
+			flat:    map[string]interface{}{"[0]": 1},
+			wantErr: true,
+		},
+		{
+			name:    "Index over limit",
+			flat:    map[string]interface{}{"a[999999999]": 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unflatten(tt.flat, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unflatten() error = %v; wantErr %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Unflatten() = (%v); want (%v)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnflattenDoesNotModifyInput(t *testing.T) {
+	m := map[string]interface{}{"x": 1}
+	list := []interface{}{map[string]interface{}{"y": 1}}
+	got, err := Unflatten(map[string]interface{}{"a": m, "a.z": 2, "b": list, "b[0].w": 3, "b[1]": 4})
+	if err != nil {
+		t.Fatalf("Unflatten() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"a": map[string]interface{}{"x": 1, "z": 2},
+		"b": []interface{}{map[string]interface{}{"y": 1, "w": 3}, 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unflatten() = %v; want %v", got, want)
+	}
+	if len(m) != 1 || len(list) != 1 || len(list[0].(map[string]interface{})) != 1 {
+		t.Errorf("Unflatten() modified its input: %v, %v", m, list)
+	}
+}
+
+func TestFlattenRoundTrip(t *testing.T) {
+	source := map[string]interface{}{
+		"a": []interface{}{
+			map[string]interface{}{"b": "x", "c": []interface{}{}},
+			2.5,
+		},
+		"d": map[string]interface{}{},
+	}
+	opts := []FlattenOption{WithSeparator("/"), WithArrayNotation(SeparatorNotation)}
+	got, err := Unflatten(Flatten(source, opts...), opts...)
+	if err != nil {
+		t.Fatalf("Unflatten() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, source) {
+		t.Errorf("round trip = (%v); want (%v)", got, source)
+	}
+}
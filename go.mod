@@ -1,3 +1,10 @@
 module github.com/lukaszraczylo/ask
 
 go 1.20
+
+require (
+	golang.org/x/term v0.29.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.30.0 // indirect
@@ -0,0 +1,84 @@
+// Package ini decodes INI files into trees ask can query.
+//
+// Each section becomes a map, and dots in section names and keys create
+// nested maps, so "max = 10" under "[database.pool]" is found at
+// "database.pool.max". Unquoted values are typed the same way in every
+// loader: true and false become bool, integers int64 and decimals float64.
+// Quoted values always stay strings.
+package ini
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/lukaszraczylo/ask"
+	"github.com/lukaszraczylo/ask/internal/scalar"
+)
+
+// Decode parses an INI document. Keys before the first section are stored
+// at the root. Lines starting with ";" or "#" are comments, as is anything
+// after " ;" or " #" in an unquoted value. A repeated key keeps its last value.
+func Decode(data []byte) (map[string]any, error) {
+	flat := make(map[string]any)
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == ';' || text[0] == '#' {
+			continue
+		}
+		if text[0] == '[' {
+			end := strings.IndexByte(text, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("ini: line %d: unterminated section header", line)
+			}
+			section = strings.TrimSpace(text[1:end])
+			if section == "" {
+				return nil, fmt.Errorf("ini: line %d: empty section name", line)
+			}
+			continue
+		}
+
+		sep := strings.IndexAny(text, "=:")
+		if sep <= 0 {
+			return nil, fmt.Errorf("ini: line %d: expected key = value", line)
+		}
+		key := strings.TrimSpace(text[:sep])
+		if section != "" {
+			key = section + "." + key
+		}
+		value, err := parseValue(strings.TrimSpace(text[sep+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("ini: line %d: %w", line, err)
+		}
+		flat[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	doc, err := ask.Unflatten(flat)
+	if err != nil {
+		return nil, fmt.Errorf("ini: %w", err)
+	}
+	return doc, nil
+}
+
+func parseValue(raw string) (any, error) {
+	if raw != "" && (raw[0] == '"' || raw[0] == '\'') {
+		quote := raw[0]
+		end := strings.IndexByte(raw[1:], quote)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated quoted value")
+		}
+		return raw[1 : end+1], nil
+	}
+	for _, marker := range []string{" ;", " #", "\t;", "\t#"} {
+		if i := strings.Index(raw, marker); i >= 0 {
+			raw = strings.TrimSpace(raw[:i])
+		}
+	}
+	return scalar.Infer(raw), nil
+}
@@ -0,0 +1,75 @@
+package ini
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lukaszraczylo/ask"
+)
+
+const config = `; global settings
+name = service
+debug = true
+
+[database]
+host = db.local ; inline comment
+port: 5432
+password = "p;ss # word"
+
+[database.pool]
+max = 10
+ratio = 0.75
+code = 007
+single = 'quoted'
+`
+
+func TestDecode(t *testing.T) {
+	doc, err := Decode([]byte(config))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{path: "name", want: "service"},
+		{path: "debug", want: true},
+		{path: "database.host", want: "db.local"},
+		{path: "database.port", want: int64(5432)},
+		{path: "database.password", want: "p;ss # word"},
+		{path: "database.pool.max", want: int64(10)},
+		{path: "database.pool.ratio", want: 0.75},
+		{path: "database.pool.code", want: "007"},
+		{path: "database.pool.single", want: "quoted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := ask.For(doc, tt.path).Value(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("For(%q) = (%#v); want (%#v)", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "Unterminated header", src: "[database"},
+		{name: "Empty section", src: "[]"},
+		{name: "Missing separator", src: "[a]\njust text"},
+		{name: "Unterminated quote", src: `a = "open`},
+		{name: "Section over value", src: "database = 1\n[database]\nhost = x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode([]byte(tt.src)); err == nil {
+				t.Errorf("Decode(%q) error = nil; want error", tt.src)
+			}
+		})
+	}
+}
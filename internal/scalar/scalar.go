@@ -0,0 +1,37 @@
+// Package scalar infers types for untyped configuration values, so INI and
+// dotenv files produce the same kinds of values as typed formats.
+package scalar
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Infer converts text to bool, int64 or float64 when it is written as one
+// and returns it unchanged otherwise. Numbers with leading zeros such as
+// "007" stay strings, since they are usually identifiers.
+func Infer(s string) any {
+	switch strings.ToLower(s) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if s == "" || hasLeadingZero(s) {
+		return s
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if strings.ContainsAny(s, ".eE") && !strings.ContainsAny(s, "xXpP_") {
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !strings.ContainsAny(s, "iInN") {
+			return f
+		}
+	}
+	return s
+}
+
+func hasLeadingZero(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	return len(s) > 1 && s[0] == '0' && s[1] != '.'
+}
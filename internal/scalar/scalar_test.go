@@ -0,0 +1,38 @@
+package scalar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		in   string
+		want interface{}
+	}{
+		{in: "true", want: true},
+		{in: "FALSE", want: false},
+		{in: "42", want: int64(42)},
+		{in: "-7", want: int64(-7)},
+		{in: "3.5", want: 3.5},
+		{in: "1e3", want: 1000.0},
+		{in: "0.5", want: 0.5},
+		{in: "0", want: int64(0)},
+		{in: "007", want: "007"},
+		{in: "0x10", want: "0x10"},
+		{in: "1_000", want: "1_000"},
+		{in: "Inf", want: "Inf"},
+		{in: "NaN", want: "NaN"},
+		{in: "", want: ""},
+		{in: "yes", want: "yes"},
+		{in: "99999999999999999999", want: "99999999999999999999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := Infer(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Infer(%q) = (%T %v); want (%T %v)", tt.in, got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
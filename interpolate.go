@@ -0,0 +1,219 @@
+package ask
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Filter transforms a value inside a ${...} placeholder, see Interpolate.
+type Filter func(value any) (any, error)
+
+type interpolateConfig struct {
+	strict  bool
+	filters map[string]Filter
+}
+
+// InterpolateOption configures Interpolate.
+type InterpolateOption func(*interpolateConfig)
+
+// Strict makes Interpolate fail on placeholders whose path is missing and
+// that have no default, instead of replacing them with an empty string.
+func Strict() InterpolateOption {
+	return func(c *interpolateConfig) {
+		c.strict = true
+	}
+}
+
+// WithFilter registers a filter under name for use after a pipe. The
+// filters upper, lower, trim, title and json are built in; registering
+// one of them replaces it.
+func WithFilter(name string, fn Filter) InterpolateOption {
+	return func(c *interpolateConfig) {
+		c.filters[name] = fn
+	}
+}
+
+// Interpolate replaces the placeholders in tmpl with values from source:
+//
+//	Order ${order.id} for ${customer.name | upper}, ships to ${address.city:-unknown}
+//
+// A placeholder holds a path as used by For, optionally followed by ":-"
+// and a default used when the path is missing, then any number of
+// "| filter" steps applied in order. "$${" produces a literal "${".
+// Strings are inserted as they are, numbers and booleans in their usual
+// text form and maps and slices as JSON. Missing paths without a default
+// become empty, or fail under Strict; all of them are reported together.
+func Interpolate(tmpl string, source any, opts ...InterpolateOption) (string, error) {
+	c := &interpolateConfig{filters: map[string]Filter{
+		"upper": stringFilter(strings.ToUpper),
+		"lower": stringFilter(strings.ToLower),
+		"trim":  stringFilter(strings.TrimSpace),
+		"title": stringFilter(titleCase),
+		"json":  jsonFilter,
+	}}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	var b strings.Builder
+	var missing []error
+	for {
+		i := strings.Index(tmpl, "${")
+		if i < 0 {
+			b.WriteString(tmpl)
+			break
+		}
+		if i > 0 && tmpl[i-1] == '$' {
+			b.WriteString(tmpl[:i-1])
+			b.WriteString("${")
+			tmpl = tmpl[i+2:]
+			continue
+		}
+		b.WriteString(tmpl[:i])
+		end := strings.IndexByte(tmpl[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("ask: interpolate: unterminated placeholder at %q", tmpl[i:])
+		}
+		placeholder := tmpl[i+2 : i+end]
+		tmpl = tmpl[i+end+1:]
+
+		text, found, err := c.expand(placeholder, source)
+		if err != nil {
+			return "", fmt.Errorf("ask: interpolate ${%s}: %w", placeholder, err)
+		}
+		if !found && c.strict {
+			missing = append(missing, fmt.Errorf("ask: interpolate ${%s}: %w", placeholder, ErrMissing))
+		}
+		b.WriteString(text)
+	}
+	if err := errors.Join(missing...); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// expand renders one placeholder. found is false when the path is missing
+// and there is no default.
+func (c *interpolateConfig) expand(placeholder string, source any) (text string, found bool, err error) {
+	parts := strings.Split(placeholder, "|")
+	path, def, hasDefault := strings.Cut(parts[0], ":-")
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", false, errors.New("empty path")
+	}
+
+	var value any = For(source, path).Value()
+	switch {
+	case value != nil:
+		found = true
+	case hasDefault:
+		value, found = strings.TrimSpace(def), true
+	default:
+		return "", false, nil
+	}
+
+	for _, name := range parts[1:] {
+		name = strings.TrimSpace(name)
+		filter, ok := c.filters[name]
+		if !ok {
+			return "", false, fmt.Errorf("unknown filter %q", name)
+		}
+		if value, err = filter(value); err != nil {
+			return "", false, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	text, err = formatValue(value)
+	return text, found, err
+}
+
+// formatValue turns a value into the text inserted into a template.
+func formatValue(value any) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	if s, err := convertString(value); err == nil {
+		return s.(string), nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func stringFilter(fn func(string) string) Filter {
+	return func(value any) (any, error) {
+		s, err := formatValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return fn(s), nil
+	}
+}
+
+func jsonFilter(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// titleCase upper-cases the first letter of every space separated word.
+func titleCase(s string) string {
+	var b strings.Builder
+	start := true
+	for _, r := range s {
+		if start {
+			b.WriteRune(unicode.ToUpper(r))
+		} else {
+			b.WriteRune(r)
+		}
+		start = unicode.IsSpace(r)
+	}
+	return b.String()
+}
+
+// FuncMap returns functions for text/template that read from a document,
+// taking the path first and the document second. Convert it with
+// html/template.FuncMap to use it there.
+//
+//	{{ ask "customer.name" . }} has {{ askInt "orders.count" . }} orders
+//
+// ask returns the raw value, or nil when missing. askString, askInt,
+// askFloat and askBool return the typed value, or the zero value when it
+// is missing or of another type. askOr returns its third argument when
+// the path is missing.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"ask": func(path string, source any) any {
+			return For(source, path).Value()
+		},
+		"askString": func(path string, source any) string {
+			s, _ := For(source, path).String("")
+			return s
+		},
+		"askInt": func(path string, source any) int64 {
+			i, _ := For(source, path).Int(0)
+			return i
+		},
+		"askFloat": func(path string, source any) float64 {
+			f, _ := For(source, path).Float(0)
+			return f
+		},
+		"askBool": func(path string, source any) bool {
+			b, _ := For(source, path).Bool(false)
+			return b
+		},
+		"askOr": func(path string, source any, def any) any {
+			if v := For(source, path).Value(); v != nil {
+				return v
+			}
+			return def
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package ask
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+const orderPayload = `{
+	"order": {"id": 1042, "total": 99.5, "paid": true, "tags": ["gift", "express"]},
+	"customer": {"name": "  ann smith  ", "email": "Ann@Example.com"}
+}`
+
+func TestInterpolate(t *testing.T) {
+	doc := decodeJSON(t, orderPayload)
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{name: "Plain text", tmpl: "no placeholders", want: "no placeholders"},
+		{name: "Paths", tmpl: "Order ${order.id} for ${customer.email}", want: "Order 1042 for Ann@Example.com"},
+		{name: "Scalars", tmpl: "${order.total} ${order.paid} ${order.tags[1]}", want: "99.5 true express"},
+		{name: "Collections as JSON", tmpl: "${order.tags}", want: `["gift","express"]`},
+		{name: "Filters", tmpl: "${customer.name | trim | title}", want: "Ann Smith"},
+		{name: "Case filters", tmpl: "${customer.email|lower} ${customer.email|upper}", want: "ann@example.com ANN@EXAMPLE.COM"},
+		{name: "JSON filter", tmpl: "${customer.email | json}", want: `"Ann@Example.com"`},
+		{name: "Default", tmpl: "ships to ${address.city:-unknown}", want: "ships to unknown"},
+		{name: "Default not used", tmpl: "${order.id:-0}", want: "1042"},
+		{name: "Filtered default", tmpl: "${address.city:-none | upper}", want: "NONE"},
+		{name: "Missing is empty", tmpl: "[${address.city}]", want: "[]"},
+		{name: "Escaped", tmpl: "cost: $${order.total} = ${order.total}", want: "cost: ${order.total} = 99.5"},
+		{name: "Lone dollar", tmpl: "$5 and $ {order.id}", want: "$5 and $ {order.id}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Interpolate(tt.tmpl, doc)
+			if err != nil {
+				t.Fatalf("Interpolate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Interpolate() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterpolateOptions(t *testing.T) {
+	doc := decodeJSON(t, orderPayload)
+
+	_, err := Interpolate("${a} ${order.id} ${b:-x} ${c}", doc, Strict())
+	if !errors.Is(err, ErrMissing) || !strings.Contains(err.Error(), "${a}") || !strings.Contains(err.Error(), "${c}") {
+		t.Errorf("Interpolate() strict error = %v; want both missing paths", err)
+	}
+
+	initials := WithFilter("initials", func(v interface{}) (interface{}, error) {
+		var out []string
+		for _, word := range strings.Fields(v.(string)) {
+			out = append(out, strings.ToUpper(word[:1]))
+		}
+		return strings.Join(out, ""), nil
+	})
+	got, err := Interpolate("${customer.name | initials}", doc, initials, Strict())
+	if err != nil || got != "AS" {
+		t.Errorf("Interpolate() with custom filter = (%q, %v); want \"AS\"", got, err)
+	}
+
+	for _, tmpl := range []string{"${order.id", "${}", "${order.id | nope}"} {
+		if _, err := Interpolate(tmpl, doc); err == nil {
+			t.Errorf("Interpolate(%q) error = nil; want error", tmpl)
+		}
+	}
+}
+
+func TestFuncMap(t *testing.T) {
+	doc := decodeJSON(t, orderPayload)
+	tmpl := template.Must(template.New("t").Funcs(FuncMap()).Parse(
+		`{{ askString "customer.email" . }} #{{ askInt "order.id" . }} {{ askFloat "order.total" . }} ` +
+			`{{ if askBool "order.paid" . }}paid{{ end }} {{ range ask "order.tags" . }}{{ . }};{{ end }} ` +
+			`{{ askOr "order.note" . "-" }} {{ askInt "customer.email" . }}`))
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, doc); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := "Ann@Example.com #1042 99.5 paid gift;express; - 0"; b.String() != want {
+		t.Errorf("Execute() = %q; want %q", b.String(), want)
+	}
+}
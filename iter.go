@@ -0,0 +1,88 @@
+//go:build go1.23
+
+package ask
+
+import (
+	"iter"
+	"reflect"
+	"strconv"
+)
+
+// All lazily yields the children of a map or slice answer. Map entries are
+// keyed by their key and slice elements by their index token ("[0]"), so
+// every yielded key can be passed back to Path. Other values yield nothing.
+func (a *Answer) All() iter.Seq2[string, *Answer] {
+	return func(yield func(string, *Answer) bool) {
+		if isSliceValue(a.value) {
+			for i, item := range a.Items() {
+				if !yield("["+strconv.Itoa(i)+"]", item) {
+					return
+				}
+			}
+			return
+		}
+		for k, v := range a.Entries() {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Items lazily yields the elements of a slice or array answer together with
+// their index. Typed slices are read in place without conversion to []any.
+func (a *Answer) Items() iter.Seq2[int, *Answer] {
+	return func(yield func(int, *Answer) bool) {
+		if s, ok := a.value.([]any); ok {
+			for i, v := range s {
+				if !yield(i, &Answer{value: v}) {
+					return
+				}
+			}
+			return
+		}
+		val := reflect.ValueOf(a.value)
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return
+		}
+		for i := 0; i < val.Len(); i++ {
+			if !yield(i, &Answer{value: val.Index(i).Interface()}) {
+				return
+			}
+		}
+	}
+}
+
+// Entries lazily yields the entries of a map answer, in the usual
+// unspecified map order. Keys that are not strings are converted to their
+// text form.
+func (a *Answer) Entries() iter.Seq2[string, *Answer] {
+	return func(yield func(string, *Answer) bool) {
+		if m, ok := a.value.(map[string]any); ok {
+			for k, v := range m {
+				if !yield(k, &Answer{value: v}) {
+					return
+				}
+			}
+			return
+		}
+		val := reflect.ValueOf(a.value)
+		if val.Kind() != reflect.Map {
+			return
+		}
+		iter := val.MapRange()
+		for iter.Next() {
+			if !yield(keyString(iter.Key().Interface()), &Answer{value: iter.Value().Interface()}) {
+				return
+			}
+		}
+	}
+}
+
+func isSliceValue(value any) bool {
+	if _, ok := value.([]any); ok {
+		return true
+	}
+	kind := reflect.ValueOf(value).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
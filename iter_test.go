@@ -0,0 +1,123 @@
+//go:build go1.23
+
+package ask
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestItems(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer *Answer
+		want   []interface{}
+	}{
+		{
+			name:   "Generic slice",
+			answer: &Answer{value: []interface{}{"a", 1}},
+			want:   []interface{}{"a", 1},
+		},
+		{
+			name:   "Typed slice",
+			answer: &Answer{value: []int{1, 2, 3}},
+			want:   []interface{}{1, 2, 3},
+		},
+		{
+			name:   "Array",
+			answer: &Answer{value: [2]string{"x", "y"}},
+			want:   []interface{}{"x", "y"},
+		},
+		{
+			name:   "Not a slice",
+			answer: &Answer{value: map[string]interface{}{"a": 1}},
+		},
+		{
+			name:   "Nil answer",
+			answer: &Answer{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []interface{}
+			for i, item := range tt.answer.Items() {
+				if i != len(got) {
+					t.Fatalf("Items() index = %d; want %d", i, len(got))
+				}
+				got = append(got, item.Value())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Items() = (%v); want (%v)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntries(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer *Answer
+		want   map[string]interface{}
+	}{
+		{
+			name:   "Generic map",
+			answer: &Answer{value: map[string]interface{}{"a": 1, "b": "x"}},
+			want:   map[string]interface{}{"a": 1, "b": "x"},
+		},
+		{
+			name:   "Typed map",
+			answer: &Answer{value: map[string]float64{"a": 1.5}},
+			want:   map[string]interface{}{"a": 1.5},
+		},
+		{
+			name:   "Non-string keys",
+			answer: &Answer{value: map[interface{}]interface{}{1: "one", "b": true}},
+			want:   map[string]interface{}{"1": "one", "b": true},
+		},
+		{
+			name:   "Not a map",
+			answer: &Answer{value: []int{1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]interface{}
+			for k, v := range tt.answer.Entries() {
+				if got == nil {
+					got = make(map[string]interface{})
+				}
+				got[k] = v.Value()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Entries() = (%v); want (%v)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAll(t *testing.T) {
+	source := map[string]interface{}{
+		"list": []int{10, 20},
+		"obj":  map[string]interface{}{"k": "v"},
+	}
+
+	for _, path := range []string{"list", "obj"} {
+		answer := For(source, path)
+		for key, child := range answer.All() {
+			if got := answer.Path(key).Value(); !reflect.DeepEqual(got, child.Value()) {
+				t.Errorf("Path(%q) = (%v); want (%v)", key, got, child.Value())
+			}
+		}
+	}
+
+	count := 0
+	for range For(source, "list").All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("All() did not stop after break")
+	}
+}
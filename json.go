@@ -0,0 +1,214 @@
+package ask
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ForJSON does the same thing as For but reads the source straight from
+// encoded JSON. Subtrees outside the path are skipped without decoding and
+// only the selected value is unmarshalled, so numbers come back as float64
+// just like with json.Unmarshal. Malformed input yields an empty answer.
+// Should an object repeat a key, the last occurrence is used, as with
+// json.Unmarshal.
+func ForJSON(data []byte, path string) *Answer {
+	if !json.Valid(data) {
+		return &Answer{}
+	}
+	raw, ok := lookupJSON(data, splitPath(path))
+	if !ok {
+		return &Answer{}
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return &Answer{}
+	}
+	return &Answer{value: value}
+}
+
+// lookupJSON returns the encoded value found at tokens.
+func lookupJSON(data []byte, tokens []string) ([]byte, bool) {
+	s := &jsonScanner{data: data}
+	for _, token := range tokens {
+		s.skipSpace()
+		if isIndexToken(token) {
+			index, ok := tokenIndex(token)
+			if !ok || !s.enterElement(index) {
+				return nil, false
+			}
+		} else if !s.enterKey(token) {
+			return nil, false
+		}
+	}
+	s.skipSpace()
+	start := s.pos
+	if !s.skipValue() {
+		return nil, false
+	}
+	return data[start:s.pos], true
+}
+
+// jsonScanner walks encoded JSON just far enough to find a value.
+type jsonScanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *jsonScanner) peek() byte {
+	if s.pos < len(s.data) {
+		return s.data[s.pos]
+	}
+	return 0
+}
+
+func (s *jsonScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\r', '\n':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// enterKey moves from the start of an object to the value stored under key.
+// The whole object is scanned so that a repeated key resolves to its last
+// value.
+func (s *jsonScanner) enterKey(key string) bool {
+	if s.peek() != '{' {
+		return false
+	}
+	s.pos++
+	found := -1
+	for {
+		s.skipSpace()
+		if s.peek() != '"' {
+			break
+		}
+		start := s.pos
+		if !s.skipString() {
+			return false
+		}
+		match := s.keyEquals(s.data[start:s.pos], key)
+		s.skipSpace()
+		if s.peek() != ':' {
+			return false
+		}
+		s.pos++
+		s.skipSpace()
+		if match {
+			found = s.pos
+		}
+		if !s.skipValue() {
+			return false
+		}
+		s.skipSpace()
+		if s.peek() != ',' {
+			break
+		}
+		s.pos++
+	}
+	if found < 0 {
+		return false
+	}
+	s.pos = found
+	return true
+}
+
+// enterElement moves from the start of an array to the element at index.
+func (s *jsonScanner) enterElement(index int) bool {
+	if s.peek() != '[' {
+		return false
+	}
+	s.pos++
+	s.skipSpace()
+	if s.peek() == ']' {
+		return false
+	}
+	for i := 0; ; i++ {
+		s.skipSpace()
+		if i == index {
+			return true
+		}
+		if !s.skipValue() {
+			return false
+		}
+		s.skipSpace()
+		if s.peek() != ',' {
+			return false
+		}
+		s.pos++
+	}
+}
+
+// keyEquals compares a quoted key with key, decoding escapes only when present.
+func (s *jsonScanner) keyEquals(quoted []byte, key string) bool {
+	raw := quoted[1 : len(quoted)-1]
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return string(raw) == key
+	}
+	var decoded string
+	if err := json.Unmarshal(quoted, &decoded); err != nil {
+		return false
+	}
+	return decoded == key
+}
+
+func (s *jsonScanner) skipString() bool {
+	s.pos++ // opening quote
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '\\':
+			s.pos += 2
+		case '"':
+			s.pos++
+			return true
+		default:
+			s.pos++
+		}
+	}
+	return false
+}
+
+func (s *jsonScanner) skipValue() bool {
+	switch s.peek() {
+	case 0:
+		return false
+	case '"':
+		return s.skipString()
+	case '{', '[':
+		depth := 0
+		for s.pos < len(s.data) {
+			switch s.data[s.pos] {
+			case '"':
+				if !s.skipString() {
+					return false
+				}
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					s.pos++
+					return true
+				}
+			}
+			s.pos++
+		}
+		return false
+	case '}', ']', ',', ':':
+		return false
+	}
+	// Numbers and literals run until the next delimiter.
+	start := s.pos
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ',', '}', ']', ' ', '\t', '\r', '\n':
+			return s.pos > start
+		}
+		s.pos++
+	}
+	return s.pos > start
+}
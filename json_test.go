@@ -0,0 +1,95 @@
+package ask
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestForJSON(t *testing.T) {
+	data := []byte(`{
+		"a": [{"b": {"c": 3}}, "x", [1, 2, {"d": null}]],
+		"skip": {"nested": ["]", "}", "\"", {"deep": [[]]}]},
+		"esc\"aped": "quote",
+		"unicode": true,
+		"num": -1.5e3,
+		"empty": {},
+		"list": []
+	}`)
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	paths := []string{
+		"",
+		"a",
+		"a[0].b.c",
+		"a[1]",
+		"a[2][2]",
+		"a[2][2].d",
+		"a[3]",
+		"a[-1]",
+		"a[x]",
+		"a.b",
+		"skip.nested[3].deep[0]",
+		"esc\"aped",
+		"unicode",
+		"num",
+		"empty",
+		"empty.x",
+		"list",
+		"list[0]",
+		"missing",
+		"num.x",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			got := ForJSON(data, path).Value()
+			want := For(decoded, path).Value()
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ForJSON(%q) = (%v); want (%v)", path, got, want)
+			}
+		})
+	}
+}
+
+func TestForJSONRepeatedKey(t *testing.T) {
+	data := []byte(`{"a": 1, "b": {"c": 1}, "a": 2, "b": {"c": 3}}`)
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"a", "b.c"} {
+		if got, want := ForJSON(data, path).Value(), For(doc, path).Value(); got != want {
+			t.Errorf("ForJSON(%q) = %v; For() = %v", path, got, want)
+		}
+	}
+}
+
+func TestForJSONMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		path string
+	}{
+		{name: "Empty input", data: ``, path: "a"},
+		{name: "Truncated object", data: `{"a": {"b": 1`, path: "a"},
+		{name: "Truncated string", data: `{"a": "b`, path: "a"},
+		{name: "Missing colon", data: `{"a" 1}`, path: "a"},
+		{name: "Invalid value", data: `{"a": tru}`, path: "a"},
+		{name: "Not an object", data: `[1]`, path: "a"},
+		{name: "Invalid sibling", data: `{"b": tru, "a": 1}`, path: "a"},
+		{name: "Trailing data", data: `{"a": 1} xx`, path: "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ForJSON([]byte(tt.data), tt.path); got.Exists() {
+				t.Errorf("ForJSON() = (%v); want missing", got.Value())
+			}
+		})
+	}
+}
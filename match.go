@@ -0,0 +1,126 @@
+package ask
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Wildcard tokens understood by the pattern based APIs such as Stream.
+// For itself treats them as ordinary keys.
+const (
+	anyKey   = "*"   // any key of a map
+	anyIndex = "[*]" // any element of a slice
+	anyDepth = ".."  // zero or more levels, see tokenizePattern
+)
+
+// tokenizePattern splits a pattern like tokenizePath and also understands
+// "..", which matches any number of levels: "..password" finds password
+// keys at any depth and "users..id" every id below users.
+func tokenizePattern(pattern string) []string {
+	var tokens []string
+	for i, part := range strings.Split(pattern, "..") {
+		if i > 0 && (len(tokens) == 0 || tokens[len(tokens)-1] != anyDepth) {
+			tokens = append(tokens, anyDepth)
+		}
+		tokens = append(tokens, tokenizePath(part)...)
+	}
+	if len(tokens) > 0 && tokens[len(tokens)-1] == anyDepth {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// matchKey reports whether a map key satisfies a pattern token.
+func matchKey(pattern, key string) bool {
+	return pattern == anyKey || (pattern == key && !isIndexToken(pattern))
+}
+
+// matchIndex reports whether a slice index satisfies a pattern token.
+func matchIndex(pattern string, index int) bool {
+	if pattern == anyIndex {
+		return true
+	}
+	i, ok := tokenIndex(pattern)
+	return ok && i == index
+}
+
+// walkPattern calls fn with the concrete tokens and value of every part of
+// source matching the pattern tokens, which may include wildcards. Absent
+// and nil values are skipped. fn must copy path if it keeps it.
+func walkPattern(source any, pattern []string, fn func(path []string, value any)) {
+	walkPatternFrom(source, pattern, nil, fn)
+}
+
+func walkPatternFrom(value any, pattern, path []string, fn func([]string, any)) {
+	if value == nil {
+		return
+	}
+	if len(pattern) == 0 {
+		fn(path, value)
+		return
+	}
+	// Cap path so siblings never share the appended element.
+	path = path[:len(path):len(path)]
+
+	switch token := pattern[0]; token {
+	case anyDepth:
+		// Match the rest here, then retry it below every child.
+		walkPatternFrom(value, pattern[1:], path, fn)
+		walkPatternFrom(value, append([]string{anyKey}, pattern...), path, fn)
+		walkPatternFrom(value, append([]string{anyIndex}, pattern...), path, fn)
+	case anyKey:
+		// Keys are visited in order so results do not depend on map
+		// iteration order.
+		if m, ok := asMap(value); ok {
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walkPatternFrom(m[k], pattern[1:], append(path, k), fn)
+			}
+		}
+	case anyIndex:
+		if s, ok := value.([]any); ok {
+			for i, child := range s {
+				walkPatternFrom(child, pattern[1:], append(path, indexToken(i)), fn)
+			}
+			return
+		}
+		if val := reflect.ValueOf(value); val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
+			for i := 0; i < val.Len(); i++ {
+				walkPatternFrom(val.Index(i).Interface(), pattern[1:], append(path, indexToken(i)), fn)
+			}
+		}
+	default:
+		walkPatternFrom(step(value, token), pattern[1:], append(path, token), fn)
+	}
+}
+
+// indexToken returns the path token selecting slice element i.
+func indexToken(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+// matchPath reports whether concrete path tokens satisfy pattern tokens of
+// the same length.
+func matchPath(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i, token := range path {
+		if index, ok := tokenIndex(token); ok {
+			if !matchIndex(pattern[i], index) {
+				return false
+			}
+			continue
+		}
+		if !matchKey(pattern[i], token) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package ask
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxPatchTable bounds the LCS table CreatePatch builds for a pair of
+// slices. Larger slices are compared position by position instead.
+const maxPatchTable = 1 << 22
+
+// Operation is a JSON Patch (RFC 6902) operation.
+type Operation struct {
+	Op    string // "add", "remove" or "replace"
+	Path  string // JSON pointer, e.g. "/users/0/name"
+	Value any    // new value for add and replace
+}
+
+// MarshalJSON writes the operation in RFC 6902 form. Value is written for
+// add and replace even when it is null, and omitted for remove.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// CreatePatch returns JSON Patch operations that turn old into new when
+// applied in order. Maps are patched key by key. Slices are aligned on
+// their longest common subsequence, so inserting or deleting an element
+// costs one operation rather than rewriting everything after it, and an
+// element changed in place is patched inside. Values in the operations are
+// shared with new, not copied.
+func CreatePatch(old, new any) []Operation {
+	var ops []Operation
+	patchValue(&ops, "", old, new)
+	return ops
+}
+
+func patchValue(ops *[]Operation, ptr string, old, new any) {
+	if oldMap, ok := asMap(old); ok {
+		if newMap, ok := asMap(new); ok {
+			patchMap(ops, ptr, oldMap, newMap)
+			return
+		}
+	}
+	if oldList, ok := asSlice(old); ok {
+		if newList, ok := asSlice(new); ok {
+			patchSlice(ops, ptr, oldList, newList)
+			return
+		}
+	}
+	if !sameValue(old, new) {
+		*ops = append(*ops, Operation{Op: "replace", Path: ptr, Value: new})
+	}
+}
+
+func patchMap(ops *[]Operation, ptr string, old, new map[string]any) {
+	keys := make([]string, 0, len(old)+len(new))
+	for k := range old {
+		keys = append(keys, k)
+	}
+	for k := range new {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		child := ptr + "/" + escapePointer(k)
+		oldValue, inOld := old[k]
+		newValue, inNew := new[k]
+		switch {
+		case !inNew:
+			*ops = append(*ops, Operation{Op: "remove", Path: child})
+		case !inOld:
+			*ops = append(*ops, Operation{Op: "add", Path: child, Value: newValue})
+		default:
+			patchValue(ops, child, oldValue, newValue)
+		}
+	}
+}
+
+func patchSlice(ops *[]Operation, ptr string, old, new []any) {
+	// Common ends need no table and keep indices stable.
+	start := 0
+	for start < len(old) && start < len(new) && sameValue(old[start], new[start]) {
+		start++
+	}
+	endOld, endNew := len(old), len(new)
+	for endOld > start && endNew > start && sameValue(old[endOld-1], new[endNew-1]) {
+		endOld--
+		endNew--
+	}
+	a, b := old[start:endOld], new[start:endNew]
+	at := func(i int) string {
+		return ptr + "/" + strconv.Itoa(start+i)
+	}
+
+	if len(a)*len(b) > maxPatchTable {
+		// Too large to align; patch position by position, removing from the
+		// end so earlier indices stay valid.
+		for i := len(a) - 1; i >= len(b); i-- {
+			*ops = append(*ops, Operation{Op: "remove", Path: at(i)})
+		}
+		for i := 0; i < len(b); i++ {
+			if i < len(a) {
+				patchValue(ops, at(i), a[i], b[i])
+			} else {
+				*ops = append(*ops, Operation{Op: "add", Path: at(i), Value: b[i]})
+			}
+		}
+		return
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[:i]
+	// and b[:j].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			switch {
+			case sameValue(a[i-1], b[j-1]):
+				lcs[i][j] = lcs[i-1][j-1] + 1
+			case lcs[i-1][j] >= lcs[i][j-1]:
+				lcs[i][j] = lcs[i-1][j]
+			default:
+				lcs[i][j] = lcs[i][j-1]
+			}
+		}
+	}
+
+	// Walk back from the end so that every operation only touches indices
+	// no earlier operation has shifted.
+	i, j := len(a), len(b)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && sameValue(a[i-1], b[j-1]):
+			i--
+			j--
+		case i > 0 && j > 0 && lcs[i-1][j-1] == lcs[i][j]:
+			// Neither element is part of the common subsequence: patch one
+			// into the other instead of removing and adding.
+			patchValue(ops, at(i-1), a[i-1], b[j-1])
+			i--
+			j--
+		case j > 0 && (i == 0 || lcs[i][j-1] >= lcs[i-1][j]):
+			*ops = append(*ops, Operation{Op: "add", Path: at(i), Value: b[j-1]})
+			j--
+		default:
+			*ops = append(*ops, Operation{Op: "remove", Path: at(i - 1)})
+			i--
+		}
+	}
+}
+
+// sameValue reports whether two values are deeply equal, treating typed
+// maps and slices like their generic counterparts.
+func sameValue(a, b any) bool {
+	var changes []Change
+	(&compareConfig{firstOnly: true}).diff(nil, a, b, &changes)
+	return len(changes) == 0
+}
+
+// escapePointer escapes a map key for use as a JSON pointer token.
+func escapePointer(key string) string {
+	if !strings.ContainsAny(key, "~/") {
+		return key
+	}
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
@@ -0,0 +1,189 @@
+package ask
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// applyPatch is a minimal RFC 6902 applier for add, remove and replace,
+// used to check that patches really turn old into new.
+func applyPatch(t *testing.T, doc interface{}, ops []Operation) interface{} {
+	t.Helper()
+	for _, op := range ops {
+		if op.Path == "" {
+			if op.Op != "replace" {
+				t.Fatalf("unsupported root operation %+v", op)
+			}
+			doc = op.Value
+			continue
+		}
+		tokens := strings.Split(op.Path[1:], "/")
+		for i, token := range tokens {
+			tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		}
+		doc = applyAt(t, doc, tokens, op)
+	}
+	return doc
+}
+
+func applyAt(t *testing.T, node interface{}, tokens []string, op Operation) interface{} {
+	t.Helper()
+	token := tokens[0]
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if len(tokens) > 1 {
+			n[token] = applyAt(t, n[token], tokens[1:], op)
+			return n
+		}
+		if op.Op == "remove" {
+			delete(n, token)
+		} else {
+			n[token] = op.Value
+		}
+		return n
+	case []interface{}:
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i > len(n) {
+			t.Fatalf("bad index in %+v", op)
+		}
+		if len(tokens) > 1 {
+			n[i] = applyAt(t, n[i], tokens[1:], op)
+			return n
+		}
+		switch op.Op {
+		case "add":
+			n = append(n, nil)
+			copy(n[i+1:], n[i:])
+			n[i] = op.Value
+		case "remove":
+			n = append(n[:i], n[i+1:]...)
+		case "replace":
+			n[i] = op.Value
+		}
+		return n
+	}
+	t.Fatalf("cannot apply %+v to %T", op, node)
+	return nil
+}
+
+func decodeJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestCreatePatch(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "Identical",
+			old:  `{"a": [1, {"b": 2}]}`,
+			new:  `{"a": [1, {"b": 2}]}`,
+			want: `null`,
+		},
+		{
+			name: "Map keys",
+			old:  `{"a": 1, "b": {"c": 2, "d": 3}, "x/y": 1, "t~": 1}`,
+			new:  `{"a": 1, "b": {"c": 5, "e": null}, "x/y": 2}`,
+			want: `[{"op":"replace","path":"/b/c","value":5},{"op":"remove","path":"/b/d"},{"op":"add","path":"/b/e","value":null},{"op":"remove","path":"/t~0"},{"op":"replace","path":"/x~1y","value":2}]`,
+		},
+		{
+			name: "Insert at front",
+			old:  `[1, 2, 3, 4, 5]`,
+			new:  `[0, 1, 2, 3, 4, 5]`,
+			want: `[{"op":"add","path":"/0","value":0}]`,
+		},
+		{
+			name: "Delete from middle",
+			old:  `["a", "b", "c", "d"]`,
+			new:  `["a", "c", "d"]`,
+			want: `[{"op":"remove","path":"/1"}]`,
+		},
+		{
+			name: "Element patched in place",
+			old:  `[{"id": 1, "n": "a"}, {"id": 2, "n": "b"}]`,
+			new:  `[{"id": 1, "n": "a"}, {"id": 2, "n": "c"}]`,
+			want: `[{"op":"replace","path":"/1/n","value":"c"}]`,
+		},
+		{
+			name: "Mixed edits",
+			old:  `[1, 2, 3, 4, 5, 6]`,
+			new:  `[1, 9, 3, 5, 6, 7]`,
+			want: `[{"op":"add","path":"/6","value":7},{"op":"remove","path":"/3"},{"op":"replace","path":"/1","value":9}]`,
+		},
+		{
+			name: "Root replaced",
+			old:  `{"a": 1}`,
+			new:  `[1]`,
+			want: `[{"op":"replace","path":"","value":[1]}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops := CreatePatch(decodeJSON(t, tt.old), decodeJSON(t, tt.new))
+			got, err := json.Marshal(ops)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("CreatePatch() = %s; want %s", got, tt.want)
+			}
+			if patched := applyPatch(t, decodeJSON(t, tt.old), ops); !reflect.DeepEqual(patched, decodeJSON(t, tt.new)) {
+				t.Errorf("applying the patch gives %v; want %s", patched, tt.new)
+			}
+		})
+	}
+}
+
+func TestCreatePatchRoundTrip(t *testing.T) {
+	docs := []string{
+		`{"users": [{"id": 1, "tags": ["a", "b"]}, {"id": 2}], "n": 1}`,
+		`{"users": [{"id": 2, "tags": []}, {"id": 1, "tags": ["b", "a", "c"]}, {"id": 3}], "m": true}`,
+		`{"users": [], "n": [1, 2, 3]}`,
+		`{"users": [{"id": 3}, {"id": 3}, {"id": 3}], "n": "1"}`,
+		`{}`,
+	}
+	for i, from := range docs {
+		for j, to := range docs {
+			ops := CreatePatch(decodeJSON(t, from), decodeJSON(t, to))
+			if patched := applyPatch(t, decodeJSON(t, from), ops); !reflect.DeepEqual(patched, decodeJSON(t, to)) {
+				t.Errorf("patch from doc %d to doc %d gives %v; want %s", i, j, patched, to)
+			}
+		}
+	}
+}
+
+func TestCreatePatchTypedValues(t *testing.T) {
+	old := map[string][]string{"tags": {"a", "b"}}
+	new := map[string]interface{}{"tags": []interface{}{"a", "b", "c"}}
+	want := []Operation{{Op: "add", Path: "/tags/2", Value: "c"}}
+	if got := CreatePatch(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("CreatePatch() = %+v; want %+v", got, want)
+	}
+}
+
+func TestCreatePatchLargeSlices(t *testing.T) {
+	old := make([]interface{}, 3000)
+	new := make([]interface{}, 2500)
+	for i := range old {
+		old[i] = float64(i)
+	}
+	for i := range new {
+		new[i] = float64(i * 2)
+	}
+	ops := CreatePatch(old, new)
+	if patched := applyPatch(t, append([]interface{}(nil), old...), ops); !reflect.DeepEqual(patched, new) {
+		t.Errorf("applying the patch for large slices does not give the new slice")
+	}
+}
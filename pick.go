@@ -0,0 +1,147 @@
+package ask
+
+import "sort"
+
+// Pick returns a new document holding only the values at paths, nested as
+// in source. Paths may use "*" for any map key and "[*]" for any slice
+// element. Picked slice elements keep their index, with nil filling the
+// positions that were not picked. Paths that do not exist are skipped.
+//
+// The result is a deep copy built from map[string]any and []any, so it can
+// be changed without affecting source.
+func Pick(source any, paths ...string) any {
+	type match struct {
+		tokens []string
+		value  any
+	}
+	var matches []match
+	for _, path := range paths {
+		walkPattern(source, splitPath(path), func(tokens []string, value any) {
+			matches = append(matches, match{tokens: append([]string(nil), tokens...), value: value})
+		})
+	}
+	// Parents go first; a path inside an already picked value is then
+	// rejected by assign as a conflict and simply skipped.
+	sort.SliceStable(matches, func(i, j int) bool {
+		return len(matches[i].tokens) < len(matches[j].tokens)
+	})
+
+	var result any
+	for _, m := range matches {
+		if len(m.tokens) == 0 {
+			return deepCopy(m.value)
+		}
+		if next, err := assign(result, m.tokens, deepCopy(m.value)); err == nil {
+			result = next
+		}
+	}
+	if result == nil {
+		if _, ok := asSlice(source); ok {
+			return []any{}
+		}
+		return map[string]any{}
+	}
+	return result
+}
+
+// Omit returns a deep copy of source without the values at paths. Paths
+// may use wildcards like in Pick. Removing a slice element shifts the
+// elements after it, just as deleting it from the slice would.
+//
+// The result is built from map[string]any and []any; source is not
+// modified.
+func Omit(source any, paths ...string) any {
+	doc := deepCopy(source)
+
+	// Each value is removed once, however many paths reach it: removing a
+	// slice element twice would take its neighbour too.
+	seen := make(map[string]bool)
+	var matches [][]string
+	for _, path := range paths {
+		walkPattern(doc, splitPath(path), func(tokens []string, _ any) {
+			key := JoinPath(tokens)
+			if !seen[key] {
+				seen[key] = true
+				matches = append(matches, append([]string(nil), tokens...))
+			}
+		})
+	}
+	// Removing from the back keeps the indices of the remaining matches
+	// valid: higher indices and deeper paths go first.
+	sort.Slice(matches, func(i, j int) bool {
+		return comparePaths(matches[i], matches[j]) > 0
+	})
+
+	for _, tokens := range matches {
+		if len(tokens) == 0 {
+			return nil
+		}
+		doc = omitPath(doc, tokens)
+	}
+	return doc
+}
+
+// omitPath removes the value at tokens from node, which holds only
+// map[string]any and []any containers, and returns the updated node.
+func omitPath(node any, tokens []string) any {
+	token := tokens[0]
+	switch n := node.(type) {
+	case map[string]any:
+		if len(tokens) == 1 {
+			delete(n, token)
+		} else if child, ok := n[token]; ok {
+			n[token] = omitPath(child, tokens[1:])
+		}
+	case []any:
+		index, ok := tokenIndex(token)
+		if !ok || index >= len(n) {
+			return node
+		}
+		if len(tokens) == 1 {
+			return append(n[:index], n[index+1:]...)
+		}
+		n[index] = omitPath(n[index], tokens[1:])
+	}
+	return node
+}
+
+// comparePaths orders concrete token paths, comparing indices by number.
+// A path sorts after its own prefix.
+func comparePaths(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ai, aIndex := tokenIndex(a[i])
+		bi, bIndex := tokenIndex(b[i])
+		switch {
+		case aIndex && bIndex && ai != bi:
+			if ai < bi {
+				return -1
+			}
+			return 1
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// deepCopy copies maps and slices recursively, converting them to
+// map[string]any and []any. Other values are returned as they are.
+func deepCopy(value any) any {
+	if m, ok := asMap(value); ok {
+		out := make(map[string]any, len(m))
+		for k, v := range m {
+			out[k] = deepCopy(v)
+		}
+		return out
+	}
+	if s, ok := asSlice(value); ok {
+		out := make([]any, len(s))
+		for i, v := range s {
+			out[i] = deepCopy(v)
+		}
+		return out
+	}
+	return value
+}
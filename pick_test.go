@@ -0,0 +1,164 @@
+package ask
+
+import (
+	"reflect"
+	"testing"
+)
+
+func pickSource() map[string]interface{} {
+	return map[string]interface{}{
+		"id": 7,
+		"user": map[string]interface{}{
+			"name":     "Ann",
+			"password": "secret",
+			"address":  map[string]string{"city": "Oslo", "zip": "0150"},
+		},
+		"items": []interface{}{
+			map[string]interface{}{"sku": "A1", "qty": 2, "price": 10},
+			map[string]interface{}{"sku": "B2", "qty": 1, "price": 5},
+		},
+	}
+}
+
+func TestPick(t *testing.T) {
+	tests := []struct {
+		name   string
+		source interface{}
+		paths  []string
+		want   interface{}
+	}{
+		{
+			name:   "Nested keys",
+			source: pickSource(),
+			paths:  []string{"id", "user.name", "user.address.city"},
+			want: map[string]interface{}{
+				"id": 7,
+				"user": map[string]interface{}{
+					"name":    "Ann",
+					"address": map[string]interface{}{"city": "Oslo"},
+				},
+			},
+		},
+		{
+			name:   "Wildcard over slice",
+			source: pickSource(),
+			paths:  []string{"items[*].sku"},
+			want: map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"sku": "A1"},
+					map[string]interface{}{"sku": "B2"},
+				},
+			},
+		},
+		{
+			name:   "Single element keeps its index",
+			source: pickSource(),
+			paths:  []string{"items[1].qty"},
+			want: map[string]interface{}{
+				"items": []interface{}{nil, map[string]interface{}{"qty": 1}},
+			},
+		},
+		{
+			name:   "Overlapping paths",
+			source: pickSource(),
+			paths:  []string{"user.address.zip", "user.address", "user.*.city"},
+			want: map[string]interface{}{
+				"user": map[string]interface{}{
+					"address": map[string]interface{}{"city": "Oslo", "zip": "0150"},
+				},
+			},
+		},
+		{
+			name:   "Nothing matched",
+			source: pickSource(),
+			paths:  []string{"missing", "items[5]"},
+			want:   map[string]interface{}{},
+		},
+		{
+			name:   "Root slice",
+			source: []interface{}{"a", "b"},
+			paths:  []string{"[1]"},
+			want:   []interface{}{nil, "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pick(tt.source, tt.paths...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Pick() = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOmit(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  interface{}
+	}{
+		{
+			name:  "Keys and wildcards",
+			paths: []string{"user.password", "items[*].price", "missing.key"},
+			want: map[string]interface{}{
+				"id": 7,
+				"user": map[string]interface{}{
+					"name":    "Ann",
+					"address": map[string]interface{}{"city": "Oslo", "zip": "0150"},
+				},
+				"items": []interface{}{
+					map[string]interface{}{"sku": "A1", "qty": 2},
+					map[string]interface{}{"sku": "B2", "qty": 1},
+				},
+			},
+		},
+		{
+			name:  "Slice elements",
+			paths: []string{"items[0]", "items[1].qty", "user", "id"},
+			want: map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"sku": "B2", "price": 5},
+				},
+			},
+		},
+		{
+			name:  "Overlapping paths",
+			paths: []string{"items[0]", "items[0]", "items[*].price", "items[0].sku", "user", "id"},
+			want: map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"sku": "B2", "qty": 1},
+				},
+			},
+		},
+		{
+			name:  "All elements",
+			paths: []string{"items[*]", "user.*"},
+			want: map[string]interface{}{
+				"id":    7,
+				"user":  map[string]interface{}{},
+				"items": []interface{}{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := pickSource()
+			if got := Omit(source, tt.paths...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Omit() = %v; want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(source, pickSource()) {
+				t.Errorf("Omit() modified its source")
+			}
+		})
+	}
+}
+
+func TestPickDoesNotShareValues(t *testing.T) {
+	source := pickSource()
+	picked := Pick(source, "items").(map[string]interface{})
+	picked["items"].([]interface{})[0].(map[string]interface{})["sku"] = "changed"
+	if got, _ := For(source, "items[0].sku").String(""); got != "A1" {
+		t.Errorf("changing the picked document changed the source to %q", got)
+	}
+}
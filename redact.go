@@ -0,0 +1,138 @@
+package ask
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactStrategy returns what a redacted value is replaced with.
+type RedactStrategy func(value any) any
+
+// MaskWith replaces every redacted value with mask.
+func MaskWith(mask string) RedactStrategy {
+	return func(any) any {
+		return mask
+	}
+}
+
+// KeepLast replaces all but the last n characters with "*", so a card
+// number becomes "************1234". Values of n characters or fewer are
+// masked completely, and a negative n counts as 0. Values that are not
+// strings are masked in their JSON form.
+func KeepLast(n int) RedactStrategy {
+	if n < 0 {
+		n = 0
+	}
+	return func(value any) any {
+		text := redactText(value)
+		length := utf8.RuneCountInString(text)
+		if length <= n {
+			return strings.Repeat("*", length)
+		}
+		keep := text
+		for i := 0; i < length-n; i++ {
+			_, size := utf8.DecodeRuneInString(keep)
+			keep = keep[size:]
+		}
+		return strings.Repeat("*", length-n) + keep
+	}
+}
+
+// Hash replaces values with "sha256:" and the hex SHA-256 digest of their
+// text, so equal values can still be correlated across log lines. Short or
+// guessable values such as PINs can be recovered from an unsalted digest;
+// mask those instead.
+func Hash() RedactStrategy {
+	return func(value any) any {
+		sum := sha256.Sum256([]byte(redactText(value)))
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+}
+
+// redactText returns strings as they are and other values as JSON.
+func redactText(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Redact returns a deep copy of source with the values at patterns replaced
+// by strategy, or by "[REDACTED]" when strategy is nil. Patterns use the
+// path grammar with "*" for any map key, "[*]" for any slice element and
+// ".." for any number of levels, e.g. "..password", "*.token" or
+// "cards[*].number". A matched map or slice is replaced as a whole.
+//
+// The copy is built from map[string]any and []any; source is not modified.
+func Redact(source any, patterns []string, strategy RedactStrategy) any {
+	if strategy == nil {
+		strategy = MaskWith("[REDACTED]")
+	}
+	doc := deepCopy(source)
+
+	// Collect first: a recursive pattern may reach the same value more
+	// than once, and every value must be replaced exactly once.
+	seen := make(map[string]bool)
+	var matches [][]string
+	for _, pattern := range patterns {
+		walkPattern(doc, tokenizePattern(pattern), func(tokens []string, _ any) {
+			key := JoinPath(tokens)
+			if !seen[key] {
+				seen[key] = true
+				matches = append(matches, append([]string(nil), tokens...))
+			}
+		})
+	}
+	// Parents first, so a value inside an already redacted one is skipped.
+	sort.SliceStable(matches, func(i, j int) bool {
+		return len(matches[i]) < len(matches[j])
+	})
+
+	for _, tokens := range matches {
+		if len(tokens) == 0 {
+			return strategy(doc)
+		}
+		replaceAt(doc, tokens, strategy)
+	}
+	return doc
+}
+
+// replaceAt replaces the value at tokens inside node, which holds only
+// map[string]any and []any containers, with fn applied to it.
+func replaceAt(node any, tokens []string, fn func(any) any) {
+	for i, token := range tokens {
+		last := i == len(tokens)-1
+		switch n := node.(type) {
+		case map[string]any:
+			value, ok := n[token]
+			if !ok {
+				return
+			}
+			if last {
+				n[token] = fn(value)
+				return
+			}
+			node = value
+		case []any:
+			index, ok := tokenIndex(token)
+			if !ok || index >= len(n) {
+				return
+			}
+			if last {
+				n[index] = fn(n[index])
+				return
+			}
+			node = n[index]
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package ask
+
+import (
+	"reflect"
+	"testing"
+)
+
+func redactSource() map[string]interface{} {
+	return map[string]interface{}{
+		"user": map[string]interface{}{
+			"name":     "Ann",
+			"password": "hunter2",
+			"session":  map[string]interface{}{"token": "abc", "id": 1},
+		},
+		"api":   map[string]interface{}{"token": "xyz"},
+		"token": "top",
+		"cards": []interface{}{
+			map[string]interface{}{"number": "4111111111111111", "pin": 1234},
+			map[string]interface{}{"number": "5500000000000004"},
+		},
+		"nested": []interface{}{
+			map[string]interface{}{"deep": map[string]interface{}{"password": "p"}},
+		},
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		strategy RedactStrategy
+		path     string
+		want     interface{}
+	}{
+		{name: "Default mask", patterns: []string{"user.password"}, path: "user.password", want: "[REDACTED]"},
+		{name: "Single level wildcard", patterns: []string{"*.token"}, path: "api.token", want: "[REDACTED]"},
+		{name: "Single level wildcard skips root", patterns: []string{"*.token"}, path: "token", want: "top"},
+		{name: "Single level wildcard skips deeper", patterns: []string{"*.token"}, path: "user.session.token", want: "abc"},
+		{name: "Recursive at root", patterns: []string{"..token"}, path: "token", want: "[REDACTED]"},
+		{name: "Recursive deep", patterns: []string{"..token"}, path: "user.session.token", want: "[REDACTED]"},
+		{name: "Recursive through slices", patterns: []string{"..password"}, path: "nested[0].deep.password", want: "[REDACTED]"},
+		{name: "Recursive below a key", patterns: []string{"user..id"}, path: "user.session.id", want: "[REDACTED]"},
+		{
+			name:     "Keep last",
+			patterns: []string{"cards[*].number"},
+			strategy: KeepLast(4),
+			path:     "cards[1].number",
+			want:     "************0004",
+		},
+		{name: "Keep last of a number", patterns: []string{"cards[0].pin"}, strategy: KeepLast(1), path: "cards[0].pin", want: "***4"},
+		{name: "Keep last of a short value", patterns: []string{"token"}, strategy: KeepLast(4), path: "token", want: "***"},
+		{name: "Keep last with negative n", patterns: []string{"token"}, strategy: KeepLast(-2), path: "token", want: "***"},
+		{
+			name:     "Hash",
+			patterns: []string{"user.name"},
+			strategy: Hash(),
+			path:     "user.name",
+			want:     "sha256:17239b6e250110330eda64a29c610bf146f89883371fab093feda03bec61b646",
+		},
+		{name: "Whole subtree", patterns: []string{"user.session"}, strategy: MaskWith("-"), path: "user.session", want: "-"},
+		{name: "Missing path", patterns: []string{"nope..x", "cards[9].number"}, path: "token", want: "top"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := redactSource()
+			got := Redact(source, tt.patterns, tt.strategy)
+			if value := For(got, tt.path).Value(); !reflect.DeepEqual(value, tt.want) {
+				t.Errorf("Redact() value at %q = %v; want %v", tt.path, value, tt.want)
+			}
+			if !reflect.DeepEqual(source, redactSource()) {
+				t.Errorf("Redact() modified its source")
+			}
+		})
+	}
+}
+
+func TestRedactOnce(t *testing.T) {
+	// "..a..b" reaches x.a.a.b along two routes; it must be hashed once.
+	source := map[string]interface{}{"a": map[string]interface{}{"a": map[string]interface{}{"b": "v"}}}
+	got := Redact(source, []string{"..a..b", "a.a.b"}, Hash())
+	if value := For(got, "a.a.b").Value(); value != Hash()("v") {
+		t.Errorf("Redact() value = %v; want a single hash of \"v\"", value)
+	}
+}
+
+func TestTokenizePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{pattern: "a.b", want: []string{"a", "b"}},
+		{pattern: "..password", want: []string{"..", "password"}},
+		{pattern: "users..id", want: []string{"users", "..", "id"}},
+		{pattern: "a....b", want: []string{"a", "..", "b"}},
+		{pattern: "cards[*]..number", want: []string{"cards", "[*]", "..", "number"}},
+		{pattern: "a..", want: []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := tokenizePattern(tt.pattern); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenizePattern(%q) = %q; want %q", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package ask
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// RuleError describes a value that broke a rule passed to Require.
+type RuleError struct {
+	Path string
+	Err  error
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("ask: %s: %v", e.Path, e.Err)
+}
+
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// Rules maps paths to the rule their values must satisfy.
+type Rules map[string]*Rule
+
+// Rule describes the expected type and bounds of a value. Rules are built
+// with IsInt, IsString and the other Is functions and refined by chaining,
+// e.g. IsInt().Min(1). Every refinement returns a new Rule, so a shared base
+// rule is never modified.
+type Rule struct {
+	kind     string
+	optional bool
+	min      *float64
+	max      *float64
+	minLen   *int
+	maxLen   *int
+	pattern  *regexp.Regexp
+}
+
+// IsInt requires a value the Int accessor accepts without truncating it:
+// an integer, or a float holding a whole number.
+func IsInt() *Rule { return &Rule{kind: "int"} }
+
+// IsFloat requires a value the Float accessor accepts.
+func IsFloat() *Rule { return &Rule{kind: "float"} }
+
+// IsString requires a value the String accessor accepts.
+func IsString() *Rule { return &Rule{kind: "string"} }
+
+// IsBool requires a value the Bool accessor accepts.
+func IsBool() *Rule { return &Rule{kind: "bool"} }
+
+// IsSlice requires a value the Slice accessor accepts.
+func IsSlice() *Rule { return &Rule{kind: "slice"} }
+
+// IsMap requires a value the Map accessor accepts.
+func IsMap() *Rule { return &Rule{kind: "map"} }
+
+// Min sets the smallest allowed number for IsInt and IsFloat rules.
+func (r *Rule) Min(v float64) *Rule {
+	return r.with(func(c *Rule) { c.min = &v })
+}
+
+// Max sets the largest allowed number for IsInt and IsFloat rules.
+func (r *Rule) Max(v float64) *Rule {
+	return r.with(func(c *Rule) { c.max = &v })
+}
+
+// MinLen sets the smallest allowed length of a string, counted in
+// characters, or of a slice or map.
+func (r *Rule) MinLen(n int) *Rule {
+	return r.with(func(c *Rule) { c.minLen = &n })
+}
+
+// MaxLen sets the largest allowed length of a string, slice or map.
+func (r *Rule) MaxLen(n int) *Rule {
+	return r.with(func(c *Rule) { c.maxLen = &n })
+}
+
+// Matches requires a string value to match re.
+func (r *Rule) Matches(re *regexp.Regexp) *Rule {
+	return r.with(func(c *Rule) { c.pattern = re })
+}
+
+// Optional allows the value to be missing. A value that is present must
+// still satisfy the rule.
+func (r *Rule) Optional() *Rule {
+	return r.with(func(c *Rule) { c.optional = true })
+}
+
+func (r *Rule) with(fn func(*Rule)) *Rule {
+	c := *r
+	fn(&c)
+	return &c
+}
+
+// Require checks every path in rules against source and reports all
+// violations together, each as a *RuleError, in path order. A missing
+// value is reported with ErrMissing unless its rule is Optional.
+func Require(source any, rules Rules) error {
+	paths := make([]string, 0, len(rules))
+	for path := range rules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var errs []error
+	for i, answer := range Extract(source, paths...) {
+		rule := rules[paths[i]]
+		if rule == nil {
+			errs = append(errs, &RuleError{Path: paths[i], Err: errors.New("rule is nil")})
+			continue
+		}
+		for _, err := range rule.check(answer) {
+			errs = append(errs, &RuleError{Path: paths[i], Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// check returns every way answer breaks the rule.
+func (r *Rule) check(answer *Answer) []error {
+	if !answer.Exists() {
+		if r.optional {
+			return nil
+		}
+		return []error{ErrMissing}
+	}
+
+	var (
+		number    float64
+		isNumber  bool
+		length    = -1
+		text      string
+		converted bool
+	)
+	switch r.kind {
+	case "int":
+		var i int64
+		i, converted = answer.Int(0)
+		converted = converted && !truncates(answer)
+		number, isNumber = float64(i), true
+	case "float":
+		number, converted = answer.Float(0)
+		isNumber = true
+	case "string":
+		text, converted = answer.String("")
+		length = utf8.RuneCountInString(text)
+	case "bool":
+		_, converted = answer.Bool(false)
+	case "slice":
+		var items []any
+		items, converted = answer.Slice(nil)
+		length = len(items)
+	case "map":
+		var entries map[string]any
+		entries, converted = answer.Map(nil)
+		length = len(entries)
+	}
+	if !converted {
+		return []error{fmt.Errorf("expected %s, got %T", r.kind, answer.Value())}
+	}
+
+	var errs []error
+	if isNumber && r.min != nil && number < *r.min {
+		errs = append(errs, fmt.Errorf("must be at least %v, got %v", *r.min, number))
+	}
+	if isNumber && r.max != nil && number > *r.max {
+		errs = append(errs, fmt.Errorf("must be at most %v, got %v", *r.max, number))
+	}
+	if length >= 0 && r.minLen != nil && length < *r.minLen {
+		errs = append(errs, fmt.Errorf("length must be at least %d, got %d", *r.minLen, length))
+	}
+	if length >= 0 && r.maxLen != nil && length > *r.maxLen {
+		errs = append(errs, fmt.Errorf("length must be at most %d, got %d", *r.maxLen, length))
+	}
+	if r.kind == "string" && r.pattern != nil && !r.pattern.MatchString(text) {
+		errs = append(errs, fmt.Errorf("must match %q", r.pattern.String()))
+	}
+	return errs
+}
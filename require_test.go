@@ -0,0 +1,106 @@
+package ask
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestRequire(t *testing.T) {
+	source := map[string]interface{}{
+		"user": map[string]interface{}{
+			"id":    float64(7),
+			"email": "ann@example.com",
+			"name":  "Ann",
+			"age":   int64(230),
+		},
+		"tags":    []interface{}{"a", "b", "c"},
+		"meta":    map[string]interface{}{},
+		"enabled": "yes",
+		"ratio":   1.9,
+		"half":    0.5,
+	}
+	email := regexp.MustCompile(`^[^@]+@[^@]+$`)
+
+	tests := []struct {
+		name  string
+		rules Rules
+		want  string
+	}{
+		{
+			name: "All satisfied",
+			rules: Rules{
+				"user.id":    IsInt().Min(1),
+				"user.email": IsString().Matches(email),
+				"user.name":  IsString().MinLen(2).MaxLen(10),
+				"tags":       IsSlice().MaxLen(10),
+				"meta":       IsMap(),
+				"ratio":      IsFloat().Optional(),
+			},
+		},
+		{
+			name: "Every violation reported in path order",
+			rules: Rules{
+				"user.name":  IsString().Matches(email).MaxLen(2),
+				"user.age":   IsInt().Min(0).Max(150),
+				"tags":       IsSlice().MinLen(5),
+				"enabled":    IsBool(),
+				"user.phone": IsString(),
+				"meta":       IsMap().MinLen(1),
+			},
+			want: "ask: enabled: expected bool, got string\n" +
+				"ask: meta: length must be at least 1, got 0\n" +
+				"ask: tags: length must be at least 5, got 3\n" +
+				"ask: user.age: must be at most 150, got 230\n" +
+				"ask: user.name: length must be at most 2, got 3\n" +
+				"ask: user.name: must match \"^[^@]+@[^@]+$\"\n" +
+				"ask: user.phone: required value is missing",
+		},
+		{
+			name:  "Optional value of the wrong type",
+			rules: Rules{"user.email": IsFloat().Optional()},
+			want:  "ask: user.email: expected float, got string",
+		},
+		{
+			name:  "Fractional value is not an int",
+			rules: Rules{"ratio": IsInt().Max(1), "half": IsInt()},
+			want: "ask: half: expected int, got float64\n" +
+				"ask: ratio: expected int, got float64",
+		},
+		{
+			name:  "Nil rule",
+			rules: Rules{"user.id": nil},
+			want:  "ask: user.id: rule is nil",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Require(source, tt.rules)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("Require() error = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireErrors(t *testing.T) {
+	err := Require(map[string]interface{}{}, Rules{"id": IsInt()})
+	if !errors.Is(err, ErrMissing) {
+		t.Errorf("errors.Is(err, ErrMissing) = false for %v", err)
+	}
+	var re *RuleError
+	if !errors.As(err, &re) || re.Path != "id" {
+		t.Errorf("errors.As(err, *RuleError) = %v; want path \"id\"", re)
+	}
+
+	base := IsInt()
+	_ = base.Min(10)
+	if err := Require(map[string]interface{}{"n": 1}, Rules{"n": base}); err != nil {
+		t.Errorf("Min() modified the base rule: %v", err)
+	}
+}
@@ -0,0 +1,533 @@
+// Package schema validates documents against JSON Schema draft 2020-12.
+//
+// Schemas and documents are plain trees, so a schema can be written in any
+// format ask reads: decode it with encoding/json or one of the yaml, toml,
+// ini or xml packages and pass the result to Compile. Numbers of any Go type
+// compare by value, so a TOML int64 5 equals a JSON float64 5.
+//
+// The core, applicator and validation vocabularies are supported. References
+// must point into the same schema, through a JSON pointer ("#/$defs/user"),
+// an $anchor ("#user") or an $id. The format keyword and the unevaluated
+// vocabulary are ignored, and pattern uses Go regular expressions.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schema is a compiled schema, safe for concurrent use.
+type Schema struct {
+	root *node
+}
+
+// node is a compiled schema object or boolean schema.
+type node struct {
+	always *bool // set for the boolean schemas true and false
+
+	ref    string
+	target *node
+
+	types      []string
+	enum       []any
+	constValue any
+	hasConst   bool
+
+	multipleOf       *float64
+	maximum          *float64
+	exclusiveMaximum *float64
+	minimum          *float64
+	exclusiveMinimum *float64
+
+	maxLength *int
+	minLength *int
+	pattern   *regexp.Regexp
+
+	maxItems    *int
+	minItems    *int
+	uniqueItems bool
+	maxContains *int
+	minContains *int
+
+	maxProperties     *int
+	minProperties     *int
+	required          []string
+	dependentRequired map[string][]string
+
+	allOf []*node
+	anyOf []*node
+	oneOf []*node
+	not   *node
+
+	ifSchema   *node
+	thenSchema *node
+	elseSchema *node
+
+	prefixItems []*node
+	items       *node
+	contains    *node
+
+	properties           map[string]*node
+	patternProperties    []patternSchema
+	additionalProperties *node
+	propertyNames        *node
+	dependentSchemas     map[string]*node
+}
+
+type patternSchema struct {
+	pattern *regexp.Regexp
+	schema  *node
+}
+
+// compiler turns a schema tree into nodes, keyed by JSON pointer so that
+// references to the same location share one node.
+type compiler struct {
+	root    any
+	nodes   map[string]*node
+	anchors map[string]*node
+	ids     map[string]string // $id to JSON pointer
+	refs    []*node
+}
+
+// Compile checks a schema and prepares it for validation. The schema is a
+// decoded tree: a map for a schema object or a bool.
+func Compile(schema any) (*Schema, error) {
+	c := &compiler{
+		root:    normalize(schema),
+		nodes:   make(map[string]*node),
+		anchors: make(map[string]*node),
+		ids:     make(map[string]string),
+	}
+	root, err := c.compile(c.root, "")
+	if err != nil {
+		return nil, err
+	}
+	// References are resolved once every location is known, so schemas
+	// may refer to themselves and to each other.
+	for i := 0; i < len(c.refs); i++ {
+		n := c.refs[i]
+		target, err := c.resolve(n.ref)
+		if err != nil {
+			return nil, err
+		}
+		n.target = target
+	}
+	return &Schema{root: root}, nil
+}
+
+// MustCompile is like Compile but panics on an invalid schema.
+func MustCompile(schema any) *Schema {
+	s, err := Compile(schema)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (c *compiler) compile(value any, ptr string) (*node, error) {
+	if n, ok := c.nodes[ptr]; ok {
+		return n, nil
+	}
+	n := &node{}
+	c.nodes[ptr] = n
+
+	if b, ok := value.(bool); ok {
+		n.always = &b
+		return n, nil
+	}
+	m, ok := value.(map[string]any)
+	if !ok {
+		return nil, c.errorf(ptr, "schema must be an object or a boolean")
+	}
+
+	if id, ok := m["$id"].(string); ok {
+		c.ids[strings.TrimSuffix(id, "#")] = ptr
+	}
+	for _, key := range []string{"$anchor", "$dynamicAnchor"} {
+		if anchor, ok := m[key].(string); ok {
+			c.anchors[anchor] = n
+		}
+	}
+	for _, key := range []string{"$ref", "$dynamicRef"} {
+		if ref, ok := m[key].(string); ok {
+			n.ref = ref
+			c.refs = append(c.refs, n)
+		}
+	}
+
+	if err := c.compileAssertions(n, m, ptr); err != nil {
+		return nil, err
+	}
+	if err := c.compileApplicators(n, m, ptr); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (c *compiler) compileAssertions(n *node, m map[string]any, ptr string) error {
+	switch t := m["type"].(type) {
+	case nil:
+	case string:
+		n.types = []string{t}
+	case []any:
+		for _, v := range t {
+			s, ok := v.(string)
+			if !ok {
+				return c.errorf(ptr+"/type", "type names must be strings")
+			}
+			n.types = append(n.types, s)
+		}
+	default:
+		return c.errorf(ptr+"/type", "type must be a string or an array")
+	}
+
+	if v, ok := m["enum"]; ok {
+		list, ok := v.([]any)
+		if !ok {
+			return c.errorf(ptr+"/enum", "enum must be an array")
+		}
+		n.enum = list
+	}
+	if v, ok := m["const"]; ok {
+		n.constValue, n.hasConst = v, true
+	}
+
+	numbers := []struct {
+		key string
+		dst **float64
+	}{
+		{"multipleOf", &n.multipleOf},
+		{"maximum", &n.maximum},
+		{"exclusiveMaximum", &n.exclusiveMaximum},
+		{"minimum", &n.minimum},
+		{"exclusiveMinimum", &n.exclusiveMinimum},
+	}
+	for _, kw := range numbers {
+		if v, ok := m[kw.key]; ok {
+			f, ok := v.(float64)
+			if !ok {
+				return c.errorf(ptr+"/"+kw.key, "%s must be a number", kw.key)
+			}
+			*kw.dst = &f
+		}
+	}
+	if n.multipleOf != nil && *n.multipleOf <= 0 {
+		return c.errorf(ptr+"/multipleOf", "multipleOf must be greater than 0")
+	}
+
+	counts := []struct {
+		key string
+		dst **int
+	}{
+		{"maxLength", &n.maxLength},
+		{"minLength", &n.minLength},
+		{"maxItems", &n.maxItems},
+		{"minItems", &n.minItems},
+		{"maxContains", &n.maxContains},
+		{"minContains", &n.minContains},
+		{"maxProperties", &n.maxProperties},
+		{"minProperties", &n.minProperties},
+	}
+	for _, kw := range counts {
+		if v, ok := m[kw.key]; ok {
+			f, ok := v.(float64)
+			if !ok || f < 0 || f != float64(int(f)) {
+				return c.errorf(ptr+"/"+kw.key, "%s must be a non-negative integer", kw.key)
+			}
+			i := int(f)
+			*kw.dst = &i
+		}
+	}
+
+	if v, ok := m["pattern"]; ok {
+		re, err := c.regexp(v, ptr+"/pattern")
+		if err != nil {
+			return err
+		}
+		n.pattern = re
+	}
+	n.uniqueItems, _ = m["uniqueItems"].(bool)
+
+	if v, ok := m["required"]; ok {
+		names, err := c.strings(v, ptr+"/required")
+		if err != nil {
+			return err
+		}
+		n.required = names
+	}
+	if v, ok := m["dependentRequired"]; ok {
+		deps, ok := v.(map[string]any)
+		if !ok {
+			return c.errorf(ptr+"/dependentRequired", "dependentRequired must be an object")
+		}
+		n.dependentRequired = make(map[string][]string, len(deps))
+		for name, list := range deps {
+			names, err := c.strings(list, ptr+"/dependentRequired/"+escape(name))
+			if err != nil {
+				return err
+			}
+			n.dependentRequired[name] = names
+		}
+	}
+	return nil
+}
+
+func (c *compiler) compileApplicators(n *node, m map[string]any, ptr string) error {
+	var err error
+	lists := []struct {
+		key string
+		dst *[]*node
+	}{
+		{"allOf", &n.allOf},
+		{"anyOf", &n.anyOf},
+		{"oneOf", &n.oneOf},
+		{"prefixItems", &n.prefixItems},
+	}
+	for _, kw := range lists {
+		v, ok := m[kw.key]
+		if !ok {
+			continue
+		}
+		list, ok := v.([]any)
+		if !ok || (len(list) == 0 && kw.key != "prefixItems") {
+			return c.errorf(ptr+"/"+kw.key, "%s must be a non-empty array", kw.key)
+		}
+		for i, sub := range list {
+			child, err := c.compile(sub, ptr+"/"+kw.key+"/"+strconv.Itoa(i))
+			if err != nil {
+				return err
+			}
+			*kw.dst = append(*kw.dst, child)
+		}
+	}
+
+	singles := []struct {
+		key string
+		dst **node
+	}{
+		{"not", &n.not},
+		{"if", &n.ifSchema},
+		{"then", &n.thenSchema},
+		{"else", &n.elseSchema},
+		{"items", &n.items},
+		{"contains", &n.contains},
+		{"additionalProperties", &n.additionalProperties},
+		{"propertyNames", &n.propertyNames},
+	}
+	for _, kw := range singles {
+		if v, ok := m[kw.key]; ok {
+			if *kw.dst, err = c.compile(v, ptr+"/"+kw.key); err != nil {
+				return err
+			}
+		}
+	}
+
+	maps := []struct {
+		key string
+		dst *map[string]*node
+	}{
+		{"properties", &n.properties},
+		{"dependentSchemas", &n.dependentSchemas},
+		{"$defs", nil},
+	}
+	for _, kw := range maps {
+		v, ok := m[kw.key]
+		if !ok {
+			continue
+		}
+		props, ok := v.(map[string]any)
+		if !ok {
+			return c.errorf(ptr+"/"+kw.key, "%s must be an object", kw.key)
+		}
+		compiled := make(map[string]*node, len(props))
+		for name, sub := range props {
+			if compiled[name], err = c.compile(sub, ptr+"/"+kw.key+"/"+escape(name)); err != nil {
+				return err
+			}
+		}
+		if kw.dst != nil {
+			*kw.dst = compiled
+		}
+	}
+
+	if v, ok := m["patternProperties"]; ok {
+		props, ok := v.(map[string]any)
+		if !ok {
+			return c.errorf(ptr+"/patternProperties", "patternProperties must be an object")
+		}
+		for _, expr := range sortedKeys(props) {
+			sub := ptr + "/patternProperties/" + escape(expr)
+			re, err := c.regexp(expr, sub)
+			if err != nil {
+				return err
+			}
+			child, err := c.compile(props[expr], sub)
+			if err != nil {
+				return err
+			}
+			n.patternProperties = append(n.patternProperties, patternSchema{pattern: re, schema: child})
+		}
+	}
+	return nil
+}
+
+// resolve finds the node a $ref points to.
+func (c *compiler) resolve(ref string) (*node, error) {
+	base, fragment, _ := strings.Cut(ref, "#")
+	ptr := ""
+	if base != "" {
+		p, ok := c.ids[base]
+		if !ok {
+			return nil, fmt.Errorf("schema: $ref %q: only references within the schema are supported", ref)
+		}
+		ptr = p
+	}
+	fragment, err := url.PathUnescape(fragment)
+	if err != nil {
+		return nil, fmt.Errorf("schema: $ref %q: %w", ref, err)
+	}
+
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		if n, ok := c.anchors[fragment]; ok {
+			return n, nil
+		}
+		return nil, fmt.Errorf("schema: $ref %q: unknown anchor", ref)
+	}
+
+	// Walk the raw tree so pointers into locations that are not schema
+	// keywords, such as the legacy "definitions", still resolve.
+	value := c.root
+	if ptr != "" {
+		value = lookupPointer(c.root, ptr)
+	}
+	if fragment != "" {
+		value = lookupPointer(value, fragment)
+		ptr += fragment
+	}
+	if value == nil {
+		return nil, fmt.Errorf("schema: $ref %q: no schema at that location", ref)
+	}
+	return c.compile(value, canonicalPointer(ptr))
+}
+
+func (c *compiler) regexp(v any, ptr string) (*regexp.Regexp, error) {
+	expr, ok := v.(string)
+	if !ok {
+		return nil, c.errorf(ptr, "pattern must be a string")
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, c.errorf(ptr, "%v", err)
+	}
+	return re, nil
+}
+
+func (c *compiler) strings(v any, ptr string) ([]string, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, c.errorf(ptr, "expected an array of strings")
+	}
+	names := make([]string, len(list))
+	for i, item := range list {
+		if names[i], ok = item.(string); !ok {
+			return nil, c.errorf(ptr, "expected an array of strings")
+		}
+	}
+	return names, nil
+}
+
+func (c *compiler) errorf(ptr, format string, args ...any) error {
+	if ptr == "" {
+		ptr = "/"
+	}
+	return fmt.Errorf("schema: %s: %s", ptr, fmt.Sprintf(format, args...))
+}
+
+// lookupPointer follows a JSON pointer such as "/$defs/a~1b" through value.
+func lookupPointer(value any, ptr string) any {
+	if ptr == "" {
+		return value
+	}
+	for _, token := range strings.Split(ptr[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch v := value.(type) {
+		case map[string]any:
+			value = v[token]
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			value = v[i]
+		default:
+			return nil
+		}
+	}
+	return value
+}
+
+// canonicalPointer rewrites a pointer the way compile builds them, so a
+// reference and the location it names share one node.
+func canonicalPointer(ptr string) string {
+	if ptr == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, token := range strings.Split(ptr[1:], "/") {
+		b.WriteByte('/')
+		b.WriteString(escape(strings.NewReplacer("~1", "/", "~0", "~").Replace(token)))
+	}
+	return b.String()
+}
+
+// escape escapes a key for use in a JSON pointer.
+func escape(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Error is a single validation failure.
+type Error struct {
+	Path    string // instance path in For syntax, "" for the whole document
+	Keyword string // schema keyword that failed, e.g. "minLength"
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return "schema: " + e.Message
+	}
+	return "schema: " + e.Path + ": " + e.Message
+}
+
+// Validate checks doc against the schema. Every failure is reported, each
+// as an *Error, joined with errors.Join in path order.
+func (s *Schema) Validate(doc any) error {
+	v := &validator{}
+	v.validate(s.root, normalize(doc), nil)
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		a, b := v.errs[i], v.errs[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Keyword < b.Keyword
+	})
+	errs := make([]error, len(v.errs))
+	for i, e := range v.errs {
+		errs[i] = e
+	}
+	return errors.Join(errs...)
+}
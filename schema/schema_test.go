@@ -0,0 +1,258 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lukaszraczylo/ask/yaml"
+)
+
+func decode(t *testing.T, src string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(src), &v); err != nil {
+		t.Fatalf("invalid test JSON %s: %v", src, err)
+	}
+	return v
+}
+
+// failures lists "path keyword" for every error in err.
+func failures(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var list []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var se *Error
+		if errors.As(e, &se) {
+			list = append(list, se.Path+" "+se.Keyword)
+		}
+	}
+	return list
+}
+
+const userSchema = `{
+	"$defs": {
+		"tag": {"$anchor": "tag", "type": "string", "minLength": 2},
+		"address": {
+			"type": "object",
+			"properties": {"city": {"type": "string"}, "zip": {"type": "string", "pattern": "^[0-9]{5}$"}},
+			"required": ["city"]
+		}
+	},
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "maxLength": 5},
+		"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+		"email": {"type": ["string", "null"]},
+		"role": {"enum": ["admin", "user"]},
+		"version": {"const": 2},
+		"score": {"type": "number", "multipleOf": 0.5},
+		"tags": {"type": "array", "items": {"$ref": "#tag"}, "uniqueItems": true, "maxItems": 3},
+		"address": {"$ref": "#/$defs/address"},
+		"point": {"prefixItems": [{"type": "number"}, {"type": "number"}], "items": false}
+	},
+	"patternProperties": {"^x-": {"type": "string"}},
+	"additionalProperties": false,
+	"required": ["name", "age"],
+	"dependentRequired": {"email": ["name"]}
+}`
+
+func TestValidate(t *testing.T) {
+	s, err := Compile(decode(t, userSchema))
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		doc  string
+		want []string
+	}{
+		{
+			name: "Valid",
+			doc:  `{"name": "ann", "age": 30, "email": null, "role": "admin", "version": 2, "score": 1.5, "tags": ["go", "db"], "address": {"city": "Oslo", "zip": "01234"}, "point": [1, 2], "x-trace": "abc"}`,
+		},
+		{
+			name: "Missing required",
+			doc:  `{}`,
+			want: []string{"age required", "name required"},
+		},
+		{
+			name: "Wrong types",
+			doc:  `{"name": 1, "age": 1.5, "email": false}`,
+			want: []string{"age type", "email type", "name type"},
+		},
+		{
+			name: "Ranges and strings",
+			doc:  `{"name": "toolong", "age": 150, "score": 1.2}`,
+			want: []string{"age exclusiveMaximum", "name maxLength", "score multipleOf"},
+		},
+		{
+			name: "Enum and const",
+			doc:  `{"name": "a", "age": 1, "role": "root", "version": 3}`,
+			want: []string{"role enum", "version const"},
+		},
+		{
+			name: "Array items through anchor",
+			doc:  `{"name": "a", "age": 1, "tags": ["go", "x", "go", "db"]}`,
+			want: []string{"tags maxItems", "tags uniqueItems", "tags[1] minLength"},
+		},
+		{
+			name: "Nested reference",
+			doc:  `{"name": "a", "age": 1, "address": {"zip": "abc"}}`,
+			want: []string{"address.city required", "address.zip pattern"},
+		},
+		{
+			name: "Prefix items and additional properties",
+			doc:  `{"name": "a", "age": 1, "point": [1, "2", 3], "x-n": 1, "extra": true}`,
+			want: []string{"extra additionalProperties", "point[1] type", "point[2] false", "x-n type"},
+		},
+		{
+			name: "Root type",
+			doc:  `[]`,
+			want: []string{" type"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Validate(decode(t, tt.doc))
+			if got := failures(err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() errors = %v (%v); want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateApplicators(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		doc    string
+		want   []string
+	}{
+		{name: "anyOf match", schema: `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`, doc: `3`},
+		{name: "anyOf none", schema: `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`, doc: `true`, want: []string{" anyOf"}},
+		{name: "oneOf both", schema: `{"oneOf": [{"type": "number"}, {"type": "integer"}]}`, doc: `3`, want: []string{" oneOf"}},
+		{name: "oneOf single", schema: `{"oneOf": [{"type": "number"}, {"type": "integer"}]}`, doc: `3.5`},
+		{name: "allOf", schema: `{"allOf": [{"minimum": 1}, {"maximum": 2}]}`, doc: `5`, want: []string{" maximum"}},
+		{name: "not", schema: `{"not": {"type": "null"}}`, doc: `null`, want: []string{" not"}},
+		{
+			name:   "if then",
+			schema: `{"if": {"properties": {"kind": {"const": "card"}}}, "then": {"required": ["number"]}, "else": {"required": ["iban"]}}`,
+			doc:    `{"kind": "card"}`,
+			want:   []string{"number required"},
+		},
+		{
+			name:   "if else",
+			schema: `{"if": {"properties": {"kind": {"const": "card"}}}, "then": {"required": ["number"]}, "else": {"required": ["iban"]}}`,
+			doc:    `{"kind": "bank"}`,
+			want:   []string{"iban required"},
+		},
+		{name: "contains", schema: `{"contains": {"type": "string"}, "maxContains": 1}`, doc: `[1, "a", "b"]`, want: []string{" maxContains"}},
+		{name: "contains none", schema: `{"contains": {"type": "string"}}`, doc: `[1, 2]`, want: []string{" contains"}},
+		{name: "minContains zero", schema: `{"contains": {"type": "string"}, "minContains": 0}`, doc: `[1]`},
+		{name: "propertyNames", schema: `{"propertyNames": {"pattern": "^[a-z]+$"}}`, doc: `{"ok": 1, "Bad": 2}`, want: []string{"Bad propertyNames"}},
+		{
+			name:   "dependentSchemas",
+			schema: `{"dependentSchemas": {"card": {"required": ["cvv"]}}}`,
+			doc:    `{"card": "4111"}`,
+			want:   []string{"cvv required"},
+		},
+		{name: "Object sizes", schema: `{"minProperties": 2}`, doc: `{"a": 1}`, want: []string{" minProperties"}},
+		{name: "Decimal multipleOf", schema: `{"items": {"multipleOf": 0.1}}`, doc: `[0.3, 0.7, 1.1, 12, 0.35]`, want: []string{"[4] multipleOf"}},
+		{name: "Integer multipleOf", schema: `{"items": {"multipleOf": 3}}`, doc: `[9, 3e300, 10, 4.5]`, want: []string{"[2] multipleOf", "[3] multipleOf"}},
+		{name: "Integer as float", schema: `{"type": "integer"}`, doc: `1.0`},
+		{name: "Unicode length", schema: `{"maxLength": 2}`, doc: `"żó"`},
+		{name: "Boolean schema", schema: `false`, doc: `1`, want: []string{" false"}},
+		{
+			name:   "Recursive reference",
+			schema: `{"$defs": {"tree": {"type": "object", "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/tree"}}, "name": {"type": "string"}}}}, "$ref": "#/$defs/tree"}`,
+			doc:    `{"name": "root", "children": [{"name": "a"}, {"children": [{"name": 1}]}]}`,
+			want:   []string{"children[1].children[0].name type"},
+		},
+		{
+			name:   "Reference by id",
+			schema: `{"$defs": {"pos": {"$id": "positive.json", "exclusiveMinimum": 0}}, "items": {"$ref": "positive.json"}}`,
+			doc:    `[1, 0]`,
+			want:   []string{"[1] exclusiveMinimum"},
+		},
+		{
+			name:   "Legacy definitions",
+			schema: `{"definitions": {"n": {"type": "number"}}, "$ref": "#/definitions/n"}`,
+			doc:    `"x"`,
+			want:   []string{" type"},
+		},
+		{name: "Self reference loop", schema: `{"$ref": "#"}`, doc: `1`, want: []string{" $ref"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Compile(decode(t, tt.schema))
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			err = s.Validate(decode(t, tt.doc))
+			if got := failures(err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() errors = %v (%v); want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateYAMLSchema(t *testing.T) {
+	doc, err := yaml.Decode([]byte(`
+type: object
+properties:
+  port: {type: integer, minimum: 1, maximum: 65535}
+  hosts: {type: array, minItems: 1}
+required: [port]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Compile(doc)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	if err := s.Validate(map[string]interface{}{"port": int64(8080), "hosts": []string{"a"}}); err != nil {
+		t.Errorf("Validate() error = %v; want nil", err)
+	}
+	err = s.Validate(map[interface{}]interface{}{"port": uint16(0), "hosts": []string{}})
+	if got, want := failures(err), []string{"hosts minItems", "port minimum"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() errors = %v; want %v", got, want)
+	}
+	if got, want := err.Error(), "schema: hosts: must have at least 1 items\nschema: port: must be at least 1"; got != want {
+		t.Errorf("Error() = %q; want %q", got, want)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "Not a schema", schema: `3`},
+		{name: "Bad type", schema: `{"type": 1}`},
+		{name: "Bad pattern", schema: `{"pattern": "("}`},
+		{name: "Negative length", schema: `{"minLength": -1}`},
+		{name: "Zero multipleOf", schema: `{"multipleOf": 0}`},
+		{name: "Empty anyOf", schema: `{"anyOf": []}`},
+		{name: "Unknown anchor", schema: `{"$ref": "#nope"}`},
+		{name: "Missing pointer", schema: `{"$ref": "#/$defs/nope"}`},
+		{name: "Remote reference", schema: `{"$ref": "https://example.com/s.json"}`},
+		{name: "Nested error", schema: `{"properties": {"a": {"required": "x"}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(decode(t, tt.schema)); err == nil {
+				t.Errorf("Compile(%s) error = nil; want error", tt.schema)
+			}
+		})
+	}
+}
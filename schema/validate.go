@@ -0,0 +1,394 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lukaszraczylo/ask"
+)
+
+// maxDepth stops references that loop without descending into the
+// document, such as {"$ref": "#"} at the root.
+const maxDepth = 512
+
+type validator struct {
+	errs  []*Error
+	depth int
+}
+
+// validate checks value against n, recording failures under path.
+func (v *validator) validate(n *node, value any, path []string) {
+	v.depth++
+	defer func() { v.depth-- }()
+	if v.depth > maxDepth {
+		v.fail(path, "$ref", "schema references loop without consuming the document")
+		return
+	}
+
+	if n.always != nil {
+		if !*n.always {
+			v.fail(path, "false", "no value is allowed here")
+		}
+		return
+	}
+	if n.target != nil {
+		v.validate(n.target, value, path)
+	}
+
+	v.checkType(n, value, path)
+	v.checkEnum(n, value, path)
+	switch val := value.(type) {
+	case float64:
+		v.checkNumber(n, val, path)
+	case string:
+		v.checkString(n, val, path)
+	case []any:
+		v.checkArray(n, val, path)
+	case map[string]any:
+		v.checkObject(n, val, path)
+	}
+	v.checkCombinators(n, value, path)
+}
+
+// valid reports whether value satisfies n without recording failures.
+func (v *validator) valid(n *node, value any, path []string) bool {
+	sub := &validator{depth: v.depth}
+	sub.validate(n, value, path)
+	return len(sub.errs) == 0
+}
+
+func (v *validator) fail(path []string, keyword, format string, args ...any) {
+	v.errs = append(v.errs, &Error{
+		Path:    ask.JoinPath(path),
+		Keyword: keyword,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) checkType(n *node, value any, path []string) {
+	if len(n.types) == 0 {
+		return
+	}
+	actual := typeOf(value)
+	for _, t := range n.types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return
+		}
+	}
+	v.fail(path, "type", "expected %s, got %s", strings.Join(n.types, " or "), actual)
+}
+
+func (v *validator) checkEnum(n *node, value any, path []string) {
+	if n.hasConst && !reflect.DeepEqual(n.constValue, value) {
+		v.fail(path, "const", "must be %s", encode(n.constValue))
+	}
+	if n.enum == nil {
+		return
+	}
+	for _, allowed := range n.enum {
+		if reflect.DeepEqual(allowed, value) {
+			return
+		}
+	}
+	v.fail(path, "enum", "must be one of %s", encode(n.enum))
+}
+
+func (v *validator) checkNumber(n *node, f float64, path []string) {
+	if n.multipleOf != nil {
+		if !isMultiple(f, *n.multipleOf) {
+			v.fail(path, "multipleOf", "must be a multiple of %v", *n.multipleOf)
+		}
+	}
+	if n.maximum != nil && f > *n.maximum {
+		v.fail(path, "maximum", "must be at most %v", *n.maximum)
+	}
+	if n.exclusiveMaximum != nil && f >= *n.exclusiveMaximum {
+		v.fail(path, "exclusiveMaximum", "must be less than %v", *n.exclusiveMaximum)
+	}
+	if n.minimum != nil && f < *n.minimum {
+		v.fail(path, "minimum", "must be at least %v", *n.minimum)
+	}
+	if n.exclusiveMinimum != nil && f <= *n.exclusiveMinimum {
+		v.fail(path, "exclusiveMinimum", "must be greater than %v", *n.exclusiveMinimum)
+	}
+}
+
+// isMultiple reports whether f is a whole multiple of d. Both are taken
+// at their shortest decimal form, so 0.3 is a multiple of 0.1 even though
+// the float64 quotient is not whole.
+func isMultiple(f, d float64) bool {
+	x, ok := decimalRat(f)
+	if !ok {
+		return false
+	}
+	y, ok := decimalRat(d)
+	if !ok || y.Sign() == 0 {
+		return false
+	}
+	return x.Quo(x, y).IsInt()
+}
+
+func decimalRat(f float64) (*big.Rat, bool) {
+	return new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+func (v *validator) checkString(n *node, s string, path []string) {
+	length := utf8.RuneCountInString(s)
+	if n.maxLength != nil && length > *n.maxLength {
+		v.fail(path, "maxLength", "must be at most %d characters long", *n.maxLength)
+	}
+	if n.minLength != nil && length < *n.minLength {
+		v.fail(path, "minLength", "must be at least %d characters long", *n.minLength)
+	}
+	if n.pattern != nil && !n.pattern.MatchString(s) {
+		v.fail(path, "pattern", "must match %q", n.pattern.String())
+	}
+}
+
+func (v *validator) checkArray(n *node, list []any, path []string) {
+	if n.maxItems != nil && len(list) > *n.maxItems {
+		v.fail(path, "maxItems", "must have at most %d items", *n.maxItems)
+	}
+	if n.minItems != nil && len(list) < *n.minItems {
+		v.fail(path, "minItems", "must have at least %d items", *n.minItems)
+	}
+	if n.uniqueItems {
+	unique:
+		for i := range list {
+			for j := i + 1; j < len(list); j++ {
+				if reflect.DeepEqual(list[i], list[j]) {
+					v.fail(path, "uniqueItems", "items %d and %d are equal", i, j)
+					break unique
+				}
+			}
+		}
+	}
+
+	for i, item := range list {
+		switch {
+		case i < len(n.prefixItems):
+			v.validate(n.prefixItems[i], item, appendPath(path, index(i)))
+		case n.items != nil:
+			v.validate(n.items, item, appendPath(path, index(i)))
+		}
+	}
+
+	if n.contains == nil {
+		return
+	}
+	matches := 0
+	for i, item := range list {
+		if v.valid(n.contains, item, appendPath(path, index(i))) {
+			matches++
+		}
+	}
+	min := 1
+	if n.minContains != nil {
+		min = *n.minContains
+	}
+	if matches < min {
+		v.fail(path, "contains", "must contain at least %d matching items, found %d", min, matches)
+	}
+	if n.maxContains != nil && matches > *n.maxContains {
+		v.fail(path, "maxContains", "must contain at most %d matching items, found %d", *n.maxContains, matches)
+	}
+}
+
+func (v *validator) checkObject(n *node, m map[string]any, path []string) {
+	if n.maxProperties != nil && len(m) > *n.maxProperties {
+		v.fail(path, "maxProperties", "must have at most %d properties", *n.maxProperties)
+	}
+	if n.minProperties != nil && len(m) < *n.minProperties {
+		v.fail(path, "minProperties", "must have at least %d properties", *n.minProperties)
+	}
+	for _, name := range n.required {
+		if _, ok := m[name]; !ok {
+			v.fail(appendPath(path, name), "required", "is required")
+		}
+	}
+	for name, deps := range n.dependentRequired {
+		if _, ok := m[name]; !ok {
+			continue
+		}
+		for _, dep := range deps {
+			if _, ok := m[dep]; !ok {
+				v.fail(appendPath(path, dep), "dependentRequired", "is required when %s is present", name)
+			}
+		}
+	}
+	for name, sub := range n.dependentSchemas {
+		if _, ok := m[name]; ok {
+			v.validate(sub, m, path)
+		}
+	}
+
+	for _, name := range sortedKeys(m) {
+		value := m[name]
+		childPath := appendPath(path, name)
+		if n.propertyNames != nil && !v.valid(n.propertyNames, name, path) {
+			v.fail(childPath, "propertyNames", "property name is not allowed")
+		}
+
+		matched := false
+		if sub, ok := n.properties[name]; ok {
+			matched = true
+			v.validate(sub, value, childPath)
+		}
+		for _, pp := range n.patternProperties {
+			if pp.pattern.MatchString(name) {
+				matched = true
+				v.validate(pp.schema, value, childPath)
+			}
+		}
+		if !matched && n.additionalProperties != nil {
+			if a := n.additionalProperties; a.always != nil && !*a.always {
+				v.fail(childPath, "additionalProperties", "property is not allowed")
+				continue
+			}
+			v.validate(n.additionalProperties, value, childPath)
+		}
+	}
+}
+
+func (v *validator) checkCombinators(n *node, value any, path []string) {
+	for _, sub := range n.allOf {
+		v.validate(sub, value, path)
+	}
+	if len(n.anyOf) > 0 {
+		matched := false
+		for _, sub := range n.anyOf {
+			if v.valid(sub, value, path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "anyOf", "must match at least one of %d schemas", len(n.anyOf))
+		}
+	}
+	if len(n.oneOf) > 0 {
+		matches := 0
+		for _, sub := range n.oneOf {
+			if v.valid(sub, value, path) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			v.fail(path, "oneOf", "must match exactly one of %d schemas, matched %d", len(n.oneOf), matches)
+		}
+	}
+	if n.not != nil && v.valid(n.not, value, path) {
+		v.fail(path, "not", "must not match the schema")
+	}
+	if n.ifSchema != nil {
+		if v.valid(n.ifSchema, value, path) {
+			if n.thenSchema != nil {
+				v.validate(n.thenSchema, value, path)
+			}
+		} else if n.elseSchema != nil {
+			v.validate(n.elseSchema, value, path)
+		}
+	}
+}
+
+// typeOf returns the JSON Schema type name of a normalized value. Numbers
+// without a fractional part are integers.
+func typeOf(value any) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == math.Trunc(val) && !math.IsInf(val, 0) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// normalize converts a decoded tree to the JSON data model: numbers of any
+// type become float64, maps map[string]any and slices []any, so values from
+// every decoder compare equal with reflect.DeepEqual.
+func normalize(value any) any {
+	switch val := value.(type) {
+	case nil, bool, string, float64:
+		return value
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, v := range val {
+			out[k] = normalize(v)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, v := range val {
+			out[i] = normalize(v)
+		}
+		return out
+	case json.Number:
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Map:
+		out := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = normalize(iter.Value().Interface())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = normalize(rv.Index(i).Interface())
+		}
+		return out
+	}
+	return value
+}
+
+func encode(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func index(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+// appendPath returns path with token added, never sharing storage with
+// sibling paths.
+func appendPath(path []string, token string) []string {
+	return append(path[:len(path):len(path)], token)
+}
@@ -0,0 +1,133 @@
+package ask
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrStop can be returned from a Stream or StreamNDJSON callback to stop
+// reading early. The stream function then returns nil.
+var ErrStop = errors.New("ask: stop streaming")
+
+// Stream reads JSON from r and calls fn for every value matching path.
+// The path may use "[*]" for any slice element and "*" for any map key,
+// e.g. "records[*].id". Only matched values are decoded; everything else is
+// skipped token by token, so memory stays bounded by the largest match
+// rather than by the size of the input. Several concatenated documents
+// are processed one after another.
+func Stream(r io.Reader, path string, fn func(*Answer) error) error {
+	dec := json.NewDecoder(r)
+	tokens := splitPath(path)
+	for dec.More() {
+		if err := streamMatch(dec, tokens, fn); err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// StreamNDJSON reads newline delimited JSON from r and calls fn once per
+// record with the answers for paths, as returned by Extract. Records are
+// numbered from zero.
+func StreamNDJSON(r io.Reader, paths []string, fn func(record int, answers []*Answer) error) error {
+	dec := json.NewDecoder(r)
+	for n := 0; ; n++ {
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("ask: record %d: %w", n, err)
+		}
+		if err := fn(n, Extract(value, paths...)); err != nil {
+			if errors.Is(err, ErrStop) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// streamMatch consumes the next value from dec, calling fn for every part of
+// it matching tokens.
+func streamMatch(dec *json.Decoder, tokens []string, fn func(*Answer) error) error {
+	if len(tokens) == 0 {
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		if value == nil {
+			return nil
+		}
+		return fn(&Answer{value: value})
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		// A scalar cannot contain the rest of the path.
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if matchKey(tokens[0], key.(string)) {
+				err = streamMatch(dec, tokens[1:], fn)
+			} else {
+				err = skipToken(dec)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; dec.More(); i++ {
+			if matchIndex(tokens[0], i) {
+				err = streamMatch(dec, tokens[1:], fn)
+			} else {
+				err = skipToken(dec)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Closing delimiter.
+	_, err = dec.Token()
+	return err
+}
+
+// skipToken consumes the next value from dec without building it.
+func skipToken(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			if delim == '{' || delim == '[' {
+				depth++
+			} else {
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package ask
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	data := `{
+		"meta": {"skip": [1, {"a": [2]}]},
+		"records": [
+			{"id": 1, "tags": ["a"]},
+			{"name": "no id"},
+			{"id": "two", "tags": ["b", "c"]},
+			{"id": null}
+		],
+		"other": {"x": {"id": 9}, "y": {"id": 10}}
+	}`
+
+	tests := []struct {
+		name string
+		data string
+		path string
+		want []interface{}
+	}{
+		{
+			name: "Wildcard index",
+			data: data,
+			path: "records[*].id",
+			want: []interface{}{1.0, "two"},
+		},
+		{
+			name: "Exact index",
+			data: data,
+			path: "records[2].tags[1]",
+			want: []interface{}{"c"},
+		},
+		{
+			name: "Nested wildcards",
+			data: data,
+			path: "records[*].tags[*]",
+			want: []interface{}{"a", "b", "c"},
+		},
+		{
+			name: "Wildcard key",
+			data: data,
+			path: "other.*.id",
+			want: []interface{}{9.0, 10.0},
+		},
+		{
+			name: "Whole document",
+			data: `{"a": 1}`,
+			path: "",
+			want: []interface{}{map[string]interface{}{"a": 1.0}},
+		},
+		{
+			name: "Concatenated documents",
+			data: `{"id": 1} {"id": 2}` + "\n" + `{"id": 3}`,
+			path: "id",
+			want: []interface{}{1.0, 2.0, 3.0},
+		},
+		{
+			name: "Top level array",
+			data: `[{"id": 1}, {"id": 2}]`,
+			path: "[*].id",
+			want: []interface{}{1.0, 2.0},
+		},
+		{
+			name: "No match",
+			data: data,
+			path: "records.id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []interface{}
+			err := Stream(strings.NewReader(tt.data), tt.path, func(a *Answer) error {
+				got = append(got, a.Value())
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Stream() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Stream() = (%v); want (%v)", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamStopAndErrors(t *testing.T) {
+	data := `[{"id": 1}, {"id": 2}, {"id": 3}]`
+
+	count := 0
+	err := Stream(strings.NewReader(data), "[*].id", func(a *Answer) error {
+		count++
+		return ErrStop
+	})
+	if err != nil || count != 1 {
+		t.Errorf("Stream() with ErrStop = (%v, %d calls); want (nil, 1 call)", err, count)
+	}
+
+	boom := errors.New("boom")
+	err = Stream(strings.NewReader(data), "[*].id", func(a *Answer) error {
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("Stream() error = %v; want %v", err, boom)
+	}
+
+	err = Stream(strings.NewReader(`{"a": [1, 2`), "a[*]", func(a *Answer) error {
+		return nil
+	})
+	if err == nil {
+		t.Errorf("Stream() on truncated input error = nil; want error")
+	}
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	data := `{"level": "info", "user": {"id": 1}}
+{"level": "warn"}
+
+{"level": "error", "user": {"id": 3}}
+`
+	var levels []string
+	var ids []int64
+	err := StreamNDJSON(strings.NewReader(data), []string{"level", "user.id"}, func(record int, answers []*Answer) error {
+		if record != len(levels) {
+			t.Errorf("record = %d; want %d", record, len(levels))
+		}
+		level, _ := answers[0].String("")
+		id, _ := answers[1].Int(-1)
+		levels = append(levels, level)
+		ids = append(ids, id)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamNDJSON() error = %v", err)
+	}
+	if want := []string{"info", "warn", "error"}; !reflect.DeepEqual(levels, want) {
+		t.Errorf("levels = %v; want %v", levels, want)
+	}
+	if want := []int64{1, -1, 3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %v; want %v", ids, want)
+	}
+
+	err = StreamNDJSON(strings.NewReader("{}\n{bad}\n"), []string{"a"}, func(int, []*Answer) error {
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "record 1") {
+		t.Errorf("StreamNDJSON() error = %v; want error for record 1", err)
+	}
+}
@@ -0,0 +1,624 @@
+// Package toml decodes TOML documents into trees ask can query.
+//
+// Tables become map[string]any, arrays and arrays of tables become []any,
+// integers int64, floats float64 and booleans bool. Offset date-times,
+// local date-times and local dates become time.Time (the local forms in
+// UTC); local times stay strings.
+package toml
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Decode parses a TOML document.
+func Decode(data []byte) (map[string]any, error) {
+	root := make(map[string]any)
+	p := &parser{src: string(data), line: 1, root: root, current: root, defined: map[string]bool{}, tableArrays: map[string]bool{}, inline: map[uintptr]bool{}}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+type parser struct {
+	src     string
+	pos     int
+	line    int
+	root    map[string]any
+	current map[string]any
+	defined map[string]bool // table headers seen so far
+	// tableArrays holds the arrays created by [[header]], which are the
+	// only arrays later headers may append to.
+	tableArrays map[string]bool
+	// inline holds the inline tables by identity; they are complete as
+	// written and cannot gain keys from headers or dotted keys.
+	inline map[uintptr]bool
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("toml: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) skipComment() {
+	if p.peek() == '#' {
+		for !p.eof() && p.peek() != '\n' {
+			p.pos++
+		}
+	}
+}
+
+// skipBlank skips whitespace, comments and newlines.
+func (p *parser) skipBlank() {
+	for {
+		p.skipSpace()
+		p.skipComment()
+		switch p.peek() {
+		case '\r':
+			p.pos++
+		case '\n':
+			p.pos++
+			p.line++
+		default:
+			return
+		}
+	}
+}
+
+// endOfLine requires nothing but a comment until the end of the line.
+func (p *parser) endOfLine() error {
+	p.skipSpace()
+	p.skipComment()
+	if p.peek() == '\r' {
+		p.pos++
+	}
+	switch p.peek() {
+	case 0:
+		return nil
+	case '\n':
+		p.pos++
+		p.line++
+		return nil
+	}
+	return p.errorf("unexpected %q after value", p.peek())
+}
+
+func (p *parser) parse() error {
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil
+		}
+		var err error
+		if p.peek() == '[' {
+			err = p.parseHeader()
+		} else {
+			err = p.parseKeyValue(p.current)
+		}
+		if err != nil {
+			return err
+		}
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) parseHeader() error {
+	p.pos++ // [
+	array := p.peek() == '['
+	if array {
+		p.pos++
+	}
+	p.skipSpace()
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipSpace()
+	closing := "]"
+	if array {
+		closing = "]]"
+	}
+	if !strings.HasPrefix(p.src[p.pos:], closing) {
+		return p.errorf("expected %s after table name", closing)
+	}
+	p.pos += len(closing)
+
+	parent, err := p.descend(p.root, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	name := strings.Join(keys, "\x00")
+
+	if array {
+		list, ok := parent[last].([]any)
+		if _, exists := parent[last]; exists && !(ok && p.tableArrays[name]) {
+			return p.errorf("%s is not an array of tables", strings.Join(keys, "."))
+		}
+		table := make(map[string]any)
+		parent[last] = append(list, table)
+		p.current = table
+		p.tableArrays[name] = true
+		// Headers below the new element start afresh.
+		prefix := name + "\x00"
+		for _, seen := range []map[string]bool{p.defined, p.tableArrays} {
+			for key := range seen {
+				if strings.HasPrefix(key, prefix) {
+					delete(seen, key)
+				}
+			}
+		}
+		return nil
+	}
+
+	if p.defined[name] {
+		return p.errorf("table %s defined twice", strings.Join(keys, "."))
+	}
+	p.defined[name] = true
+	switch existing := parent[last].(type) {
+	case nil:
+		table := make(map[string]any)
+		parent[last] = table
+		p.current = table
+	case map[string]any:
+		// Created implicitly by a dotted key or a sub-table header.
+		if p.isInline(existing) {
+			return p.errorf("%s is an inline table and cannot be extended", strings.Join(keys, "."))
+		}
+		p.current = existing
+	default:
+		return p.errorf("%s is already defined as a value", strings.Join(keys, "."))
+	}
+	return nil
+}
+
+// descend walks keys from table, creating tables as needed and entering
+// the last element of arrays of tables.
+func (p *parser) descend(table map[string]any, keys []string) (map[string]any, error) {
+	for _, key := range keys {
+		switch next := table[key].(type) {
+		case nil:
+			child := make(map[string]any)
+			table[key] = child
+			table = child
+		case map[string]any:
+			table = next
+		case []any:
+			last, ok := lastTable(next)
+			if !ok {
+				return nil, p.errorf("%s is not a table", key)
+			}
+			table = last
+		default:
+			return nil, p.errorf("%s is already defined as a value", key)
+		}
+		if p.isInline(table) {
+			return nil, p.errorf("%s is an inline table and cannot be extended", key)
+		}
+	}
+	return table, nil
+}
+
+func (p *parser) isInline(table map[string]any) bool {
+	return p.inline[reflect.ValueOf(table).Pointer()]
+}
+
+func lastTable(list []any) (map[string]any, bool) {
+	if len(list) == 0 {
+		return nil, false
+	}
+	table, ok := list[len(list)-1].(map[string]any)
+	return table, ok
+}
+
+func (p *parser) parseKeyValue(table map[string]any) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipSpace()
+	if p.peek() != '=' {
+		return p.errorf("expected = after key %s", strings.Join(keys, "."))
+	}
+	p.pos++
+	p.skipSpace()
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	parent, err := p.descend(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if _, exists := parent[last]; exists {
+		return p.errorf("key %s defined twice", strings.Join(keys, "."))
+	}
+	parent[last] = value
+	return nil
+}
+
+// parseKey parses a possibly dotted key.
+func (p *parser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpace()
+		var key string
+		var err error
+		switch c := p.peek(); {
+		case c == '"':
+			key, err = p.parseBasicString()
+		case c == '\'':
+			key, err = p.parseLiteralString()
+		default:
+			start := p.pos
+			for !p.eof() && isBareKeyChar(p.peek()) {
+				p.pos++
+			}
+			key = p.src[start:p.pos]
+			if key == "" {
+				err = p.errorf("expected key, found %q", c)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		p.skipSpace()
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *parser) parseValue() (any, error) {
+	rest := p.src[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, `"""`):
+		return p.parseMultilineBasicString()
+	case strings.HasPrefix(rest, "'''"):
+		return p.parseMultilineLiteralString()
+	case strings.HasPrefix(rest, `"`):
+		return p.parseBasicString()
+	case strings.HasPrefix(rest, "'"):
+		return p.parseLiteralString()
+	case strings.HasPrefix(rest, "["):
+		return p.parseArray()
+	case strings.HasPrefix(rest, "{"):
+		table, err := p.parseInlineTable()
+		if err != nil {
+			return nil, err
+		}
+		p.inline[reflect.ValueOf(table).Pointer()] = true
+		return table, nil
+	case strings.HasPrefix(rest, "true"):
+		p.pos += 4
+		return true, nil
+	case strings.HasPrefix(rest, "false"):
+		p.pos += 5
+		return false, nil
+	}
+	return p.parseNumberOrDate()
+}
+
+func (p *parser) parseArray() (any, error) {
+	p.pos++ // [
+	list := make([]any, 0)
+	for {
+		p.skipBlank()
+		if p.peek() == ']' {
+			p.pos++
+			return list, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+		p.skipBlank()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return list, nil
+		default:
+			return nil, p.errorf("expected , or ] in array")
+		}
+	}
+}
+
+func (p *parser) parseInlineTable() (map[string]any, error) {
+	p.pos++ // {
+	table := make(map[string]any)
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.pos++
+		return table, nil
+	}
+	for {
+		p.skipSpace()
+		if err := p.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return table, nil
+		default:
+			return nil, p.errorf("expected , or } in inline table")
+		}
+	}
+}
+
+func (p *parser) parseLiteralString() (string, error) {
+	p.pos++ // '
+	end := strings.IndexAny(p.src[p.pos:], "'\n")
+	if end < 0 || p.src[p.pos+end] != '\'' {
+		return "", p.errorf("unterminated string")
+	}
+	s := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+	return s, nil
+}
+
+func (p *parser) parseMultilineLiteralString() (string, error) {
+	p.pos += 3
+	p.trimLeadingNewline()
+	end := strings.Index(p.src[p.pos:], "'''")
+	if end < 0 {
+		return "", p.errorf("unterminated string")
+	}
+	// Up to two quotes may directly precede the closing delimiter.
+	for extra := 0; extra < 2 && p.pos+end+3 < len(p.src) && p.src[p.pos+end+3] == '\''; extra++ {
+		end++
+	}
+	s := p.src[p.pos : p.pos+end]
+	p.line += strings.Count(s, "\n")
+	p.pos += end + 3
+	return s, nil
+}
+
+func (p *parser) trimLeadingNewline() {
+	if strings.HasPrefix(p.src[p.pos:], "\r\n") {
+		p.pos += 2
+		p.line++
+	} else if p.peek() == '\n' {
+		p.pos++
+		p.line++
+	}
+}
+
+func (p *parser) parseBasicString() (string, error) {
+	p.pos++ // "
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.peek()
+		if c == '"' {
+			p.pos++
+			return b.String(), nil
+		}
+		if c == '\\' {
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+			continue
+		}
+		b.WriteByte(c)
+		p.pos++
+	}
+}
+
+func (p *parser) parseMultilineBasicString() (string, error) {
+	p.pos += 3
+	p.trimLeadingNewline()
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+		if strings.HasPrefix(p.src[p.pos:], `"""`) {
+			// Up to two quotes may directly precede the closing delimiter.
+			extra := 0
+			for extra < 2 && p.pos+3+extra < len(p.src) && p.src[p.pos+3+extra] == '"' {
+				extra++
+			}
+			b.WriteString(strings.Repeat(`"`, extra))
+			p.pos += 3 + extra
+			return b.String(), nil
+		}
+		c := p.peek()
+		if c == '\\' {
+			// A backslash at the end of a line trims the following whitespace.
+			after := strings.TrimLeft(p.src[p.pos+1:], " \t")
+			if strings.HasPrefix(after, "\n") || strings.HasPrefix(after, "\r\n") {
+				p.pos++
+				for !p.eof() && strings.IndexByte(" \t\r\n", p.peek()) >= 0 {
+					if p.peek() == '\n' {
+						p.line++
+					}
+					p.pos++
+				}
+				continue
+			}
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+			continue
+		}
+		if c == '\n' {
+			p.line++
+		}
+		b.WriteByte(c)
+		p.pos++
+	}
+}
+
+func (p *parser) parseEscape(b *strings.Builder) error {
+	p.pos++ // backslash
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case 'e':
+		b.WriteByte(0x1b)
+	case '"':
+		b.WriteByte('"')
+	case '\\':
+		b.WriteByte('\\')
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.src) {
+			return p.errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid unicode escape")
+		}
+		b.WriteRune(rune(code))
+		p.pos += size
+	default:
+		return p.errorf("invalid escape \\%c", c)
+	}
+	return nil
+}
+
+var dateLayouts = []string{
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+func (p *parser) parseNumberOrDate() (any, error) {
+	start := p.pos
+	for !p.eof() && isValueChar(p.peek()) {
+		p.pos++
+	}
+	// A date may be followed by a space and a time.
+	if p.pos-start == 10 && p.peek() == ' ' && p.pos+3 < len(p.src) && isDigit(p.src[p.pos+1]) && p.src[p.pos+3] == ':' {
+		p.pos++
+		for !p.eof() && isValueChar(p.peek()) {
+			p.pos++
+		}
+	}
+	token := p.src[start:p.pos]
+	if token == "" {
+		return nil, p.errorf("expected value")
+	}
+
+	switch strings.TrimLeft(token, "+-") {
+	case "inf":
+		if token[0] == '-' {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+
+	if len(token) >= 10 && token[4] == '-' && isDigit(token[0]) {
+		// TOML allows a lowercase t and z as well.
+		upper := strings.ToUpper(token)
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, upper); err == nil {
+				return t, nil
+			}
+		}
+		return nil, p.errorf("invalid date-time %q", token)
+	}
+	if len(token) >= 5 && token[2] == ':' && isDigit(token[0]) {
+		if _, err := time.Parse("15:04:05.999999999", token); err != nil {
+			return nil, p.errorf("invalid time %q", token)
+		}
+		return token, nil
+	}
+
+	clean := strings.ReplaceAll(token, "_", "")
+	if len(clean) > 2 && clean[0] == '0' {
+		base := 0
+		switch clean[1] {
+		case 'x':
+			base = 16
+		case 'o':
+			base = 8
+		case 'b':
+			base = 2
+		}
+		if base != 0 {
+			i, err := strconv.ParseInt(clean[2:], base, 64)
+			if err != nil {
+				return nil, p.errorf("invalid integer %q", token)
+			}
+			return i, nil
+		}
+	}
+	if strings.ContainsAny(clean, ".eE") {
+		f, err := strconv.ParseFloat(clean, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %q", token)
+		}
+		return f, nil
+	}
+	i, err := strconv.ParseInt(clean, 10, 64)
+	if err != nil {
+		return nil, p.errorf("invalid value %q", token)
+	}
+	return i, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isValueChar(c byte) bool {
+	return isBareKeyChar(c) || c == '+' || c == '.' || c == ':'
+}
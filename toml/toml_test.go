@@ -0,0 +1,188 @@
+package toml
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lukaszraczylo/ask"
+)
+
+const config = `# Service configuration
+title = "TOML \"example\""
+enabled = true
+
+[owner]
+name = 'Tom'
+dob = 1979-05-27T07:32:00-08:00
+
+[database]
+ports = [ 8000, 8001,
+  8002, ]  # trailing comma
+data = [ ["delta", "phi"], [3.14] ]
+temp_targets = { cpu = 79.5, case = 72.0 }
+pool.max = 1_000
+pool.idle = 0x1F
+
+[servers.alpha]
+ip = "10.0.0.1"
+
+[[products]]
+name = "Hammer"
+sku = 738594937
+
+[[products]]
+
+[[products]]
+name = "Nail"
+colors.primary = "gray"
+
+[products.details]
+size = 0o17
+
+[misc]
+multi = """
+Roses are red
+Violets are \
+    blue"""
+raw = '''C:\path\'''
+quoted."key~quoted" = -3e2
+local = 1979-05-27
+localtime = 07:32:00
+spaced = 1979-05-27 07:32:00Z
+lower = 1979-05-27t07:32:00z
+"inf" = -inf
+nan = nan
+unicode = "\u00e9"
+`
+
+func TestDecode(t *testing.T) {
+	doc, err := Decode([]byte(config))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{path: "title", want: `TOML "example"`},
+		{path: "enabled", want: true},
+		{path: "owner.name", want: "Tom"},
+		{path: "owner.dob", want: time.Date(1979, 5, 27, 7, 32, 0, 0, time.FixedZone("", -8*3600))},
+		{path: "database.ports[2]", want: int64(8002)},
+		{path: "database.data[0][1]", want: "phi"},
+		{path: "database.data[1][0]", want: 3.14},
+		{path: "database.temp_targets.case", want: 72.0},
+		{path: "database.pool.max", want: int64(1000)},
+		{path: "database.pool.idle", want: int64(31)},
+		{path: "servers.alpha.ip", want: "10.0.0.1"},
+		{path: "products[0].sku", want: int64(738594937)},
+		{path: "products[1]", want: map[string]interface{}{}},
+		{path: "products[2].colors.primary", want: "gray"},
+		{path: "products[2].details.size", want: int64(15)},
+		{path: "misc.multi", want: "Roses are red\nViolets are blue"},
+		{path: "misc.raw", want: `C:\path\`},
+		{path: "misc.quoted.key~quoted", want: -300.0},
+		{path: "misc.local", want: time.Date(1979, 5, 27, 0, 0, 0, 0, time.UTC)},
+		{path: "misc.localtime", want: "07:32:00"},
+		{path: "misc.spaced", want: time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC)},
+		{path: "misc.lower", want: time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC)},
+		{path: "misc.inf", want: math.Inf(-1)},
+		{path: "misc.unicode", want: "é"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := ask.For(doc, tt.path).Value()
+			if gt, ok := got.(time.Time); ok {
+				if !gt.Equal(tt.want.(time.Time)) {
+					t.Errorf("For(%q) = (%v); want (%v)", tt.path, got, tt.want)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("For(%q) = (%#v); want (%#v)", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	if f, _ := ask.For(doc, "misc.nan").Float(0); !math.IsNaN(f) {
+		t.Errorf("misc.nan = %v; want NaN", f)
+	}
+}
+
+func TestDecodeArrayOfTables(t *testing.T) {
+	doc, err := Decode([]byte(`
+[[fruits]]
+name = "apple"
+
+[fruits.physical]
+color = "red"
+
+[[fruits.varieties]]
+name = "red delicious"
+
+[[fruits]]
+name = "banana"
+
+[fruits.physical]
+color = "yellow"
+
+[[fruits.varieties]]
+name = "plantain"
+`))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := map[string]interface{}{"fruits": []interface{}{
+		map[string]interface{}{
+			"name":      "apple",
+			"physical":  map[string]interface{}{"color": "red"},
+			"varieties": []interface{}{map[string]interface{}{"name": "red delicious"}},
+		},
+		map[string]interface{}{
+			"name":      "banana",
+			"physical":  map[string]interface{}{"color": "yellow"},
+			"varieties": []interface{}{map[string]interface{}{"name": "plantain"}},
+		},
+	}}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("Decode() = %v; want %v", doc, want)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "Duplicate key", src: "a = 1\na = 2"},
+		{name: "Duplicate table", src: "[a]\n[a]"},
+		{name: "Table over value", src: "a = 1\n[a]"},
+		{name: "Missing equals", src: "a 1"},
+		{name: "Unterminated string", src: `a = "abc`},
+		{name: "Unterminated array", src: "a = [1, 2"},
+		{name: "Bad escape", src: `a = "\q"`},
+		{name: "Trailing garbage", src: "a = 1 2"},
+		{name: "Invalid number", src: "a = 12abc"},
+		{name: "Unclosed header", src: "[a"},
+		{name: "Array of tables over table", src: "[a]\n[[a]]"},
+		{name: "Array of tables over static array", src: "a = [1, 2]\n[[a]]\nx = 1"},
+		{name: "Array of tables over inline tables", src: "a = [{x = 1}]\n[[a]]"},
+		{name: "Sub-table twice in one element", src: "[[a]]\n[a.b]\n[a.b]"},
+		{name: "Header extending inline table", src: "[a]\nb = {c = 1}\n[a.b]\nd = 2"},
+		{name: "Header below inline table", src: "a = {b = {}}\n[a.b.c]"},
+		{name: "Dotted key extending inline table", src: "a = {b = 1}\na.c = 2"},
+		{name: "Header into inline array element", src: "a = [{b = 1}]\n[a.c]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode([]byte(tt.src)); err == nil {
+				t.Errorf("Decode(%q) error = nil; want error", tt.src)
+			}
+		})
+	}
+}
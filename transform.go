@@ -0,0 +1,273 @@
+package ask
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Mapping describes where a value produced by Transform comes from.
+type Mapping struct {
+	// From is the source path. With wildcards ("*", "[*]" or "..") every
+	// match is collected into a slice.
+	From string `json:"from"`
+	// Default is used when From matches nothing.
+	Default any `json:"default,omitempty"`
+	// Convert names a converter applied to the value, or to every element
+	// when From has wildcards. See WithConverter for the built-in names.
+	Convert string `json:"convert,omitempty"`
+}
+
+// UnmarshalJSON accepts either a full mapping object or just the source
+// path as a string. Whole numbers in Default become int64.
+func (m *Mapping) UnmarshalJSON(data []byte) error {
+	*m = Mapping{}
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &m.From)
+	}
+	type plain Mapping
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode((*plain)(m)); err != nil {
+		return err
+	}
+	m.Default = fromJSONNumbers(m.Default)
+	return nil
+}
+
+// Spec maps target paths to the mappings that fill them. A target path may
+// contain one "[*]", which receives the matches of a wildcard From one per
+// index, so {"users[*].id": "data[*].uid"} builds one user per element.
+type Spec map[string]Mapping
+
+// ParseSpec reads a Spec from JSON such as
+//
+//	{"user.id": "data.attributes.uid", "user.age": {"from": "data.age", "convert": "int", "default": 0}}
+func ParseSpec(data []byte) (Spec, error) {
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("ask: invalid transform spec: %w", err)
+	}
+	return spec, nil
+}
+
+// fromJSONNumbers turns json.Number values into int64 when they are whole
+// and float64 otherwise.
+func fromJSONNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, child := range v {
+			v[k] = fromJSONNumbers(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = fromJSONNumbers(child)
+		}
+	}
+	return value
+}
+
+// Converter changes a value on its way into the target document.
+type Converter func(value any) (any, error)
+
+type transformConfig struct {
+	converters map[string]Converter
+}
+
+// TransformOption configures Transform.
+type TransformOption func(*transformConfig)
+
+// WithConverter registers a converter under name for use in Mapping.Convert.
+// The names "string", "int", "float" and "bool" are built in; they follow
+// the Answer accessors and also parse numbers and booleans from strings.
+// Registering one of them replaces it.
+func WithConverter(name string, fn Converter) TransformOption {
+	return func(c *transformConfig) {
+		c.converters[name] = fn
+	}
+}
+
+// Transform builds a new document from source as described by spec. Targets
+// whose source is missing and have no default are left out. Values are
+// copied, so changing the result never affects source or spec.
+func Transform(source any, spec Spec, opts ...TransformOption) (map[string]any, error) {
+	c := &transformConfig{converters: map[string]Converter{
+		"string": convertString,
+		"int":    convertInt,
+		"float":  convertFloat,
+		"bool":   convertBool,
+	}}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	targets := make([]string, 0, len(spec))
+	for target := range spec {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	var result any = make(map[string]any)
+	for _, target := range targets {
+		m := spec[target]
+		var convert Converter
+		if m.Convert != "" {
+			if convert = c.converters[m.Convert]; convert == nil {
+				return nil, fmt.Errorf("ask: transform %q: unknown converter %q", target, m.Convert)
+			}
+		}
+		tokens := splitPath(target)
+		if len(tokens) == 0 || isIndexToken(tokens[0]) {
+			return nil, fmt.Errorf("ask: transform %q: target must start with a map key", target)
+		}
+
+		values, found, err := transformValues(source, m, convert)
+		if err != nil {
+			return nil, fmt.Errorf("ask: transform %q: %w", target, err)
+		}
+		if !found {
+			if m.Default == nil {
+				continue
+			}
+			values = m.Default
+		}
+		values = deepCopy(values)
+
+		spread := -1
+		for i, token := range tokens {
+			if token == anyIndex {
+				spread = i
+				break
+			}
+		}
+		if spread < 0 {
+			if result, err = assign(result, tokens, values); err != nil {
+				return nil, fmt.Errorf("ask: transform %q: %w", target, err)
+			}
+			continue
+		}
+
+		list, ok := asSlice(values)
+		if !ok {
+			list = []any{values}
+		}
+		for i, value := range list {
+			at := append(append(append([]string(nil), tokens[:spread]...), indexToken(i)), tokens[spread+1:]...)
+			if result, err = assign(result, at, value); err != nil {
+				return nil, fmt.Errorf("ask: transform %q: %w", target, err)
+			}
+		}
+	}
+	return result.(map[string]any), nil
+}
+
+// transformValues reads the source of m. found is false when nothing
+// matched, so the default applies.
+func transformValues(source any, m Mapping, convert Converter) (value any, found bool, err error) {
+	tokens := tokenizePattern(m.From)
+	wildcard := false
+	for _, token := range tokens {
+		if token == anyKey || token == anyIndex || token == anyDepth {
+			wildcard = true
+			break
+		}
+	}
+
+	if !wildcard {
+		value = For(source, m.From).Value()
+		if value == nil {
+			return nil, false, nil
+		}
+		if convert != nil {
+			if value, err = convert(value); err != nil {
+				return nil, false, err
+			}
+		}
+		return value, true, nil
+	}
+
+	var list []any
+	walkPattern(source, tokens, func(path []string, v any) {
+		if err != nil {
+			return
+		}
+		if convert != nil {
+			if v, err = convert(v); err != nil {
+				err = fmt.Errorf("%s: %w", JoinPath(path), err)
+				return
+			}
+		}
+		list = append(list, v)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return list, len(list) > 0, nil
+}
+
+func convertString(value any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	if f, ok := (&Answer{value: value}).Float(0); ok {
+		if i, ok := exactInt(value); ok {
+			return strconv.FormatInt(i, 10), nil
+		}
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to string", value)
+}
+
+func convertInt(value any) (any, error) {
+	if s, ok := value.(string); ok {
+		i, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return i, nil
+	}
+	if i, ok := (&Answer{value: value}).Int(0); ok {
+		return i, nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to int", value)
+}
+
+func convertFloat(value any) (any, error) {
+	if s, ok := value.(string); ok {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	if f, ok := (&Answer{value: value}).Float(0); ok {
+		return f, nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to float", value)
+}
+
+func convertBool(value any) (any, error) {
+	if s, ok := value.(string); ok {
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+	if b, ok := (&Answer{value: value}).Bool(false); ok {
+		return b, nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to bool", value)
+}
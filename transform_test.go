@@ -0,0 +1,97 @@
+package ask
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const vendorPayload = `{
+	"data": {
+		"attributes": {"uid": "42", "age": "31", "active": "true"},
+		"tags": [{"name": "go"}, {"name": "db"}],
+		"members": [{"uid": 1, "email": "A@X"}, {"uid": 2, "email": "B@X"}]
+	}
+}`
+
+func TestTransform(t *testing.T) {
+	spec, err := ParseSpec([]byte(`{
+		"user.id": "data.attributes.uid",
+		"user.age": {"from": "data.attributes.age", "convert": "int"},
+		"user.active": {"from": "data.attributes.active", "convert": "bool"},
+		"user.tags": "data.tags[*].name",
+		"user.country": {"from": "data.attributes.country", "default": "PL"},
+		"user.limits": {"from": "data.limits", "default": {"max": 10}},
+		"user.nickname": "data.attributes.nickname",
+		"members[*].id": {"from": "data.members[*].uid", "convert": "string"},
+		"members[*].email": {"from": "data.members[*].email", "convert": "lower"},
+		"emails": "data..email"
+	}`))
+	if err != nil {
+		t.Fatalf("ParseSpec() error = %v", err)
+	}
+
+	lower := WithConverter("lower", func(v interface{}) (interface{}, error) {
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("not a string")
+		}
+		return strings.ToLower(s), nil
+	})
+	got, err := Transform(decodeJSON(t, vendorPayload), spec, lower)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"user": map[string]interface{}{
+			"id":      "42",
+			"age":     int64(31),
+			"active":  true,
+			"tags":    []interface{}{"go", "db"},
+			"country": "PL",
+			"limits":  map[string]interface{}{"max": int64(10)},
+		},
+		"members": []interface{}{
+			map[string]interface{}{"id": "1", "email": "a@x"},
+			map[string]interface{}{"id": "2", "email": "b@x"},
+		},
+		"emails": []interface{}{"A@X", "B@X"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Transform() = %v; want %v", got, want)
+	}
+
+	// Defaults must not be shared between results.
+	got["user"].(map[string]interface{})["limits"].(map[string]interface{})["max"] = 0
+	again, _ := Transform(decodeJSON(t, vendorPayload), spec, lower)
+	if v := For(again, "user.limits.max").Value(); v != int64(10) {
+		t.Errorf("default changed to %v by editing an earlier result", v)
+	}
+}
+
+func TestTransformErrors(t *testing.T) {
+	source := decodeJSON(t, vendorPayload)
+	tests := []struct {
+		name string
+		spec Spec
+	}{
+		{name: "Unknown converter", spec: Spec{"a": {From: "data", Convert: "nope"}}},
+		{name: "Failed conversion", spec: Spec{"a": {From: "data.tags[*].name", Convert: "int"}}},
+		{name: "Conflicting targets", spec: Spec{"a": {From: "data.attributes.uid"}, "a.b": {From: "data.attributes.age"}}},
+		{name: "Slice at root", spec: Spec{"[0]": {From: "data"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Transform(source, tt.spec); err == nil {
+				t.Errorf("Transform() error = nil; want error")
+			}
+		})
+	}
+
+	if _, err := ParseSpec([]byte(`{"a": 1}`)); err == nil {
+		t.Errorf("ParseSpec() error = nil for a numeric mapping; want error")
+	}
+}
@@ -0,0 +1,172 @@
+package ask
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Watched holds a document that can be replaced at runtime, for example a
+// configuration file that is edited while the program runs. Reads never
+// block: For always sees either the old or the new document in full.
+type Watched struct {
+	doc  atomic.Pointer[watchedDoc]
+	load func() (any, error)
+
+	reloadMu sync.Mutex // serialises reloads and their notifications
+
+	subsMu sync.Mutex
+	subs   map[int]*subscription
+	nextID int
+}
+
+type watchedDoc struct {
+	value any
+}
+
+type subscription struct {
+	pattern []string
+	fn      func(path string, old, new *Answer)
+}
+
+// NewWatched loads the initial document with load and keeps load for
+// Reload.
+func NewWatched(load func() (any, error)) (*Watched, error) {
+	w := &Watched{load: load, subs: make(map[int]*subscription)}
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	w.doc.Store(&watchedDoc{value: value})
+	return w, nil
+}
+
+// WatchFile returns a Watched reading the file at path. The contents are
+// decoded with decode, or as JSON when decode is nil. Call Poll to pick up
+// changes, or Reload from your own trigger such as a SIGHUP handler.
+func WatchFile(path string, decode func([]byte) (any, error)) (*Watched, error) {
+	if decode == nil {
+		decode = func(data []byte) (any, error) {
+			var value any
+			err := json.Unmarshal(data, &value)
+			return value, err
+		}
+	}
+	return NewWatched(func() (any, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return decode(data)
+	})
+}
+
+// For selects path from the current document, see For.
+func (w *Watched) For(path string) *Answer {
+	return For(w.doc.Load().value, path)
+}
+
+// Reload loads the document again and replaces the current one. Subscribers
+// are then notified of every path whose value changed. When loading fails
+// the current document is kept and the error returned.
+func (w *Watched) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	value, err := w.load()
+	if err != nil {
+		return err
+	}
+	old := w.doc.Swap(&watchedDoc{value: value})
+
+	w.subsMu.Lock()
+	ids := make([]int, 0, len(w.subs))
+	for id := range w.subs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	subs := make([]*subscription, len(ids))
+	for i, id := range ids {
+		subs[i] = w.subs[id]
+	}
+	w.subsMu.Unlock()
+
+	for _, sub := range subs {
+		sub.notify(old.value, value)
+	}
+	return nil
+}
+
+// Poll calls Reload every interval until ctx is done. Errors are passed to
+// onError when it is not nil; the previous document stays in place, so a
+// file caught half written is simply read again on the next tick.
+func (w *Watched) Poll(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Subscribe calls fn after every reload that changes a value matching
+// pattern, which may use "*" for any map key and "[*]" for any slice
+// element. fn receives the concrete path and the answers before and after
+// the change; a value that appeared or disappeared has a nil old or new
+// value. Values are compared deeply, so reloading an unchanged document
+// notifies nobody. fn runs on the goroutine calling Reload and must not
+// call Reload itself. The returned function cancels the subscription.
+func (w *Watched) Subscribe(pattern string, fn func(path string, old, new *Answer)) (cancel func()) {
+	w.subsMu.Lock()
+	id := w.nextID
+	w.nextID++
+	w.subs[id] = &subscription{pattern: splitPath(pattern), fn: fn}
+	w.subsMu.Unlock()
+
+	return func() {
+		w.subsMu.Lock()
+		delete(w.subs, id)
+		w.subsMu.Unlock()
+	}
+}
+
+// notify calls fn for every matching path whose value differs between the
+// two documents, in path order.
+func (s *subscription) notify(oldDoc, newDoc any) {
+	olds := make(map[string]any)
+	walkPattern(oldDoc, s.pattern, func(path []string, value any) {
+		olds[JoinPath(path)] = value
+	})
+	news := make(map[string]any)
+	walkPattern(newDoc, s.pattern, func(path []string, value any) {
+		news[JoinPath(path)] = value
+	})
+
+	var changed []string
+	for path, value := range olds {
+		if !reflect.DeepEqual(value, news[path]) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range news {
+		if _, ok := olds[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+
+	for _, path := range changed {
+		s.fn(path, &Answer{value: olds[path]}, &Answer{value: news[path]})
+	}
+}
@@ -0,0 +1,158 @@
+package ask
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type watchChange struct {
+	path     string
+	old, new interface{}
+}
+
+func TestWatchedSubscribe(t *testing.T) {
+	docs := []interface{}{
+		map[string]interface{}{
+			"feature": map[string]interface{}{
+				"flags": map[string]interface{}{"beta": false, "dark": true},
+			},
+			"hosts": []interface{}{"a", "b"},
+			"name":  "v1",
+		},
+		// Unrelated change only.
+		map[string]interface{}{
+			"feature": map[string]interface{}{
+				"flags": map[string]interface{}{"beta": false, "dark": true},
+			},
+			"hosts": []interface{}{"a", "b"},
+			"name":  "v2",
+		},
+		// One flag flipped, one added, one host replaced and one removed.
+		map[string]interface{}{
+			"feature": map[string]interface{}{
+				"flags": map[string]interface{}{"beta": true, "dark": true, "new": "x"},
+			},
+			"hosts": []interface{}{"c"},
+			"name":  "v2",
+		},
+	}
+	loads := 0
+	w, err := NewWatched(func() (interface{}, error) {
+		doc := docs[loads]
+		loads++
+		return doc, nil
+	})
+	if err != nil {
+		t.Fatalf("NewWatched() error = %v", err)
+	}
+
+	var flags, hosts, names []watchChange
+	record := func(list *[]watchChange) func(string, *Answer, *Answer) {
+		return func(path string, old, new *Answer) {
+			*list = append(*list, watchChange{path, old.Value(), new.Value()})
+		}
+	}
+	w.Subscribe("feature.flags.*", record(&flags))
+	w.Subscribe("hosts[*]", record(&hosts))
+	cancel := w.Subscribe("name", record(&names))
+	cancel()
+
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if len(flags) != 0 || len(hosts) != 0 {
+		t.Errorf("unrelated reload notified flags %v, hosts %v", flags, hosts)
+	}
+	if got, _ := w.For("name").String(""); got != "v2" {
+		t.Errorf("For(\"name\") = %q after Reload(); want \"v2\"", got)
+	}
+
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	wantFlags := []watchChange{
+		{"feature.flags.beta", false, true},
+		{"feature.flags.new", nil, "x"},
+	}
+	if !reflect.DeepEqual(flags, wantFlags) {
+		t.Errorf("flag changes = %v; want %v", flags, wantFlags)
+	}
+	wantHosts := []watchChange{
+		{"hosts[0]", "a", "c"},
+		{"hosts[1]", "b", nil},
+	}
+	if !reflect.DeepEqual(hosts, wantHosts) {
+		t.Errorf("host changes = %v; want %v", hosts, wantHosts)
+	}
+	if len(names) != 0 {
+		t.Errorf("cancelled subscription notified %v", names)
+	}
+}
+
+func TestWatchedReloadError(t *testing.T) {
+	fail := false
+	w, err := NewWatched(func() (interface{}, error) {
+		if fail {
+			return nil, errors.New("broken")
+		}
+		return map[string]interface{}{"a": 1}, nil
+	})
+	if err != nil {
+		t.Fatalf("NewWatched() error = %v", err)
+	}
+	fail = true
+	if err := w.Reload(); err == nil {
+		t.Errorf("Reload() error = nil; want error")
+	}
+	if got := w.For("a").Value(); got != 1 {
+		t.Errorf("For(\"a\") = %v after failed Reload(); want the previous document", got)
+	}
+
+	if _, err := NewWatched(func() (interface{}, error) { return nil, errors.New("broken") }); err == nil {
+		t.Errorf("NewWatched() error = nil; want error")
+	}
+}
+
+func TestWatchFilePoll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"level": "info"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	w, err := WatchFile(path, nil)
+	if err != nil {
+		t.Fatalf("WatchFile() error = %v", err)
+	}
+
+	changed := make(chan string, 1)
+	w.Subscribe("level", func(path string, old, new *Answer) {
+		s, _ := new.String("")
+		changed <- s
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Poll(ctx, time.Millisecond, func(error) {})
+		close(done)
+	}()
+
+	if err := os.WriteFile(path, []byte(`{"level": "debug"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-changed:
+		if got != "debug" {
+			t.Errorf("notified level = %q; want \"debug\"", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change notification after the file was rewritten")
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,127 @@
+// Package xml decodes XML documents into trees ask can query.
+//
+// Elements become map keys and repeated elements become slices, so the
+// second <item> of <order> is found at "order.item[1]". Attributes are
+// stored under the attribute prefix ("@id") and text next to attributes or
+// child elements under the text key ("#text"). An element holding nothing
+// but text becomes that string, so For(doc, "order.note").String works.
+package xml
+
+import (
+	"bytes"
+	stdxml "encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type config struct {
+	attrPrefix string
+	textKey    string
+	arrays     map[string]bool
+}
+
+// Option configures Decode.
+type Option func(*config)
+
+// WithAttrPrefix sets the prefix of attribute keys, "@" by default.
+func WithAttrPrefix(prefix string) Option {
+	return func(c *config) {
+		c.attrPrefix = prefix
+	}
+}
+
+// WithTextKey sets the key holding text of elements that also have
+// attributes or children, "#text" by default.
+func WithTextKey(key string) Option {
+	return func(c *config) {
+		c.textKey = key
+	}
+}
+
+// WithArrays makes the named elements slices even when they occur once,
+// so paths such as "order.item[0]" work regardless of the item count.
+func WithArrays(names ...string) Option {
+	return func(c *config) {
+		for _, name := range names {
+			c.arrays[name] = true
+		}
+	}
+}
+
+// element is an element being decoded.
+type element struct {
+	name     string
+	children map[string]any
+	text     strings.Builder
+}
+
+// Decode parses an XML document into a map holding its root element.
+// Namespace prefixes are dropped from element and attribute names.
+func Decode(data []byte, opts ...Option) (map[string]any, error) {
+	c := &config{attrPrefix: "@", textKey: "#text", arrays: map[string]bool{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	root := &element{children: make(map[string]any)}
+	stack := []*element{root}
+	dec := stdxml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("xml: %w", err)
+		}
+		top := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case stdxml.StartElement:
+			el := &element{name: t.Name.Local, children: make(map[string]any)}
+			for _, attr := range t.Attr {
+				el.children[c.attrPrefix+attr.Name.Local] = attr.Value
+			}
+			stack = append(stack, el)
+		case stdxml.CharData:
+			top.text.Write(t)
+		case stdxml.EndElement:
+			stack = stack[:len(stack)-1]
+			c.add(stack[len(stack)-1], top.name, c.value(top))
+		}
+	}
+	if len(stack) != 1 || len(root.children) == 0 {
+		return nil, errors.New("xml: no root element")
+	}
+	return root.children, nil
+}
+
+// value returns what an element is stored as in its parent.
+func (c *config) value(el *element) any {
+	text := strings.TrimSpace(el.text.String())
+	if len(el.children) == 0 {
+		return text
+	}
+	if text != "" {
+		el.children[c.textKey] = text
+	}
+	return el.children
+}
+
+// add stores a child value, turning repeated names into slices.
+func (c *config) add(parent *element, name string, value any) {
+	existing, ok := parent.children[name]
+	switch {
+	case !ok && c.arrays[name]:
+		parent.children[name] = []any{value}
+	case !ok:
+		parent.children[name] = value
+	default:
+		if list, isList := existing.([]any); isList {
+			parent.children[name] = append(list, value)
+		} else {
+			parent.children[name] = []any{existing, value}
+		}
+	}
+}
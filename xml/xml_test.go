@@ -0,0 +1,91 @@
+package xml
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lukaszraczylo/ask"
+)
+
+const order = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- partner order -->
+<order id="42" xmlns:p="urn:partner">
+	<customer vip="true">Ann</customer>
+	<item sku="A1"><qty>2</qty></item>
+	<item sku="B2"><qty>1</qty></item>
+	<p:note>fragile</p:note>
+	<empty/>
+	<gift/>
+</order>`
+
+func TestDecode(t *testing.T) {
+	doc, err := Decode([]byte(order))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{path: "order.@id", want: "42"},
+		{path: "order.customer.#text", want: "Ann"},
+		{path: "order.customer.@vip", want: "true"},
+		{path: "order.item[1].@sku", want: "B2"},
+		{path: "order.item[0].qty", want: "2"},
+		{path: "order.note", want: "fragile"},
+		{path: "order.empty", want: ""},
+		{path: "order.item[2]", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := ask.For(doc, tt.path).Value(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("For(%q) = (%#v); want (%#v)", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	if s, ok := ask.For(doc, "order.note").String(""); !ok || s != "fragile" {
+		t.Errorf("String() = (%q, %t); want (\"fragile\", true)", s, ok)
+	}
+}
+
+func TestDecodeOptions(t *testing.T) {
+	doc, err := Decode([]byte(`<list kind="x"><item>only</item>text</list>`),
+		WithAttrPrefix("-"), WithTextKey("_"), WithArrays("item"))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"list": map[string]interface{}{
+			"-kind": "x",
+			"item":  []interface{}{"only"},
+			"_":     "text",
+		},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("Decode() = (%v); want (%v)", doc, want)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{name: "Empty", src: ``},
+		{name: "Only a comment", src: `<!-- nothing -->`},
+		{name: "Unclosed element", src: `<a><b></a>`},
+		{name: "Truncated", src: `<a><b>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode([]byte(tt.src)); err == nil {
+				t.Errorf("Decode(%q) error = nil; want error", tt.src)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Package yaml makes YAML documents queryable with ask paths.
+//
+// Decode produces plain values that work with ask.For directly. Parse keeps
+// the YAML node tree, so answers also report the line and column a value
+// came from and list mapping keys in document order.
+package yaml
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/lukaszraczylo/ask"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Decode parses a YAML document into maps, slices and scalars.
+func Decode(data []byte) (any, error) {
+	var value any
+	if err := yamlv3.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Document is a parsed YAML document that remembers source positions.
+type Document struct {
+	root *yamlv3.Node
+}
+
+// Parse parses a YAML document keeping its node tree.
+func Parse(data []byte) (*Document, error) {
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	return &Document{root: &root}, nil
+}
+
+// For selects a path from the document, see ask.For.
+func (d *Document) For(path string) *Answer {
+	return newAnswer(walk(d.root, ask.Tokenize(path)))
+}
+
+// Answer is an ask.Answer that also knows where its value is in the source.
+type Answer struct {
+	*ask.Answer
+	node *yamlv3.Node
+}
+
+func newAnswer(node *yamlv3.Node) *Answer {
+	if node == nil {
+		return &Answer{Answer: ask.For(nil, "")}
+	}
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return &Answer{Answer: ask.For(nil, "")}
+	}
+	return &Answer{Answer: ask.For(value, ""), node: node}
+}
+
+// Path does the same thing as Document.For but starts at this answer.
+func (a *Answer) Path(path string) *Answer {
+	if a.node == nil {
+		return newAnswer(nil)
+	}
+	return newAnswer(walk(a.node, ask.Tokenize(path)))
+}
+
+// Position returns the 1-based line and column of the value, or zeros when
+// the answer does not exist.
+func (a *Answer) Position() (line, column int) {
+	if a.node == nil {
+		return 0, 0
+	}
+	return a.node.Line, a.node.Column
+}
+
+// Keys returns the keys of a mapping in the order they appear in the source.
+func (a *Answer) Keys() []string {
+	node := resolve(a.node)
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	keys := make([]string, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return keys
+}
+
+// resolve follows document wrappers and aliases to the node holding data.
+func resolve(node *yamlv3.Node) *yamlv3.Node {
+	for node != nil {
+		switch {
+		case node.Kind == yamlv3.DocumentNode && len(node.Content) > 0:
+			node = node.Content[0]
+		case node.Kind == yamlv3.AliasNode:
+			node = node.Alias
+		default:
+			return node
+		}
+	}
+	return nil
+}
+
+func walk(node *yamlv3.Node, tokens []string) *yamlv3.Node {
+	node = resolve(node)
+	for _, token := range tokens {
+		if strings.HasPrefix(token, "[") && strings.HasSuffix(token, "]") {
+			node = element(node, token[1:len(token)-1])
+		} else {
+			node = lookup(node, token)
+		}
+		node = resolve(node)
+		if node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+func element(node *yamlv3.Node, indexStr string) *yamlv3.Node {
+	index, err := strconv.Atoi(strings.TrimSpace(indexStr))
+	if err != nil || node.Kind != yamlv3.SequenceNode || index < 0 || index >= len(node.Content) {
+		return nil
+	}
+	return node.Content[index]
+}
+
+// lookup finds key in a mapping, falling back to "<<" merge keys.
+func lookup(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	var merges []*yamlv3.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+		if k.Tag == "!!merge" {
+			merges = append(merges, v)
+			continue
+		}
+		if k.Value == key {
+			return v
+		}
+	}
+	for _, merge := range merges {
+		merge = resolve(merge)
+		candidates := []*yamlv3.Node{merge}
+		if merge.Kind == yamlv3.SequenceNode {
+			candidates = merge.Content
+		}
+		for _, c := range candidates {
+			if v := lookup(resolve(c), key); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
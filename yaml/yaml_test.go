@@ -0,0 +1,129 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lukaszraczylo/ask"
+)
+
+const manifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels: &labels
+    app: web
+    tier: frontend
+spec:
+  replicas: 3
+  selector:
+    matchLabels: *labels
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: nginx:1.25
+          ports:
+            - containerPort: 80
+        - name: sidecar
+          <<: {image: busybox, pull: Always}
+codes:
+  200: ok
+  404: missing
+`
+
+func TestDecode(t *testing.T) {
+	doc, err := Decode([]byte(manifest))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{path: "kind", want: "Deployment"},
+		{path: "spec.replicas", want: 3},
+		{path: "spec.selector.matchLabels.tier", want: "frontend"},
+		{path: "spec.template.spec.containers[0].ports[0].containerPort", want: 80},
+		{path: "codes.404", want: "missing"},
+		{path: "spec.missing", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := ask.For(doc, tt.path).Value(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("For(%q) = (%v); want (%v)", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Decode([]byte("a: [1")); err == nil {
+		t.Errorf("Decode() on invalid YAML error = nil; want error")
+	}
+}
+
+func TestDocument(t *testing.T) {
+	doc, err := Parse([]byte(manifest))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		want     interface{}
+		wantLine int
+		wantCol  int
+	}{
+		{path: "kind", want: "Deployment", wantLine: 2, wantCol: 7},
+		{path: "metadata.name", want: "web", wantLine: 4, wantCol: 9},
+		{path: "spec.replicas", want: 3, wantLine: 9, wantCol: 13},
+		{path: "spec.selector.matchLabels.app", want: "web", wantLine: 6, wantCol: 10},
+		{path: "spec.template.spec.containers[1].name", want: "sidecar", wantLine: 19, wantCol: 17},
+		{path: "spec.template.spec.containers[1].image", want: "busybox", wantLine: 20, wantCol: 23},
+		{path: "codes.200", want: "ok", wantLine: 22, wantCol: 8},
+		{path: "spec.template.spec.containers[2]", want: nil},
+		{path: "kind.x", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			answer := doc.For(tt.path)
+			if got := answer.Value(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("For(%q) = (%v); want (%v)", tt.path, got, tt.want)
+			}
+			line, col := answer.Position()
+			if line != tt.wantLine || col != tt.wantCol {
+				t.Errorf("Position() = (%d, %d); want (%d, %d)", line, col, tt.wantLine, tt.wantCol)
+			}
+		})
+	}
+}
+
+func TestAnswer(t *testing.T) {
+	doc, err := Parse([]byte(manifest))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if got, want := doc.For("").Keys(), []string{"apiVersion", "kind", "metadata", "spec", "codes"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v; want %v", got, want)
+	}
+	if got := doc.For("kind").Keys(); got != nil {
+		t.Errorf("Keys() on scalar = %v; want nil", got)
+	}
+
+	containers := doc.For("spec.template.spec.containers")
+	if n, ok := containers.Path("[0].ports[0].containerPort").Int(0); !ok || n != 80 {
+		t.Errorf("Path().Int() = (%d, %t); want (80, true)", n, ok)
+	}
+	if line, _ := containers.Path("[0].image").Position(); line != 16 {
+		t.Errorf("Path().Position() line = %d; want 16", line)
+	}
+	if doc.For("missing").Path("x").Exists() {
+		t.Errorf("Path() on missing answer exists; want missing")
+	}
+	if items, ok := containers.Slice(nil); !ok || len(items) != 2 {
+		t.Errorf("Slice() = (%v, %t); want 2 items", items, ok)
+	}
+}